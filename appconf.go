@@ -6,15 +6,45 @@ package appconf
 import (
 	"errors"
 	"fmt"
+	"net"
+	"net/url"
+	"regexp"
+	"sync"
 	"time"
 )
 
+var (
+	// ErrReadOnly is returned when trying to update a read-only configuration, such as a snapshot.
+	ErrReadOnly = errors.New("read-only configuration")
+)
+
 // AppConf is the main data type used to interact with configuration values.
+//
+// An AppConfig is safe for concurrent use. The values are stored as a tree of Nodes which is never modified
+// once published. Updates, such as reloads or runtime overrides, build a new tree and replace the current one
+// atomically.
 type AppConfig struct {
-	n *Node
+	lock       sync.RWMutex
+	reloadLock sync.Mutex
+	n          *Node
+	layers     []*Node
+	overrides  *Node
+	readOnly   bool
+	loaders    []Loader
+
+	// path is the key path of n in the root configuration; it is set for sub-configurations.
+	path    KeyPath
+	secrets *secretKeys
+
+	listMergeStrategies []listMergeRule
+	noInterpolation     bool
+
+	changeListeners []func(old, new *AppConfig)
+	errorListeners  []func(error)
 }
 
-// HasKey returns whether c contains key which may be nested key.
+// HasKey returns whether c contains key which may be nested key. Keys with a null value are reported as
+// existing.
 func (c *AppConfig) HasKey(key string) bool {
 	_, err := c.get(key)
 	return err == nil || errors.Is(err, ErrNotAScalar)
@@ -24,7 +54,7 @@ func (c *AppConfig) HasKey(key string) bool {
 func (c *AppConfig) Sub(key string) *AppConfig {
 	s, err := c.SubE(key)
 	if err != nil {
-		return &AppConfig{NewNode("")}
+		return &AppConfig{n: NewNode(""), readOnly: true}
 	}
 
 	return s
@@ -32,11 +62,39 @@ func (c *AppConfig) Sub(key string) *AppConfig {
 
 // SubE returns the sub-structure of c rooted at key or an error if the key does not exist.
 func (c *AppConfig) SubE(key string) (*AppConfig, error) {
-	n, err := c.get(key)
-	if err != nil {
-		return nil, err
+	s := c.Snapshot()
+	path := ParseKeyPath(key)
+
+	n := s.n.resolve(path)
+	if n == nil {
+		return nil, fmt.Errorf("%w: %s", ErrNoSuchKey, key)
+	}
+
+	sub := &AppConfig{
+		n:        n,
+		layers:   make([]*Node, 0, len(s.layers)),
+		readOnly: true,
+		path:     append(s.path[:len(s.path):len(s.path)], path...),
+		secrets:  s.secrets,
 	}
-	return &AppConfig{n: n}, nil
+	for _, l := range s.layers {
+		if ln := l.resolve(path); ln != nil {
+			sub.layers = append(sub.layers, ln)
+		}
+	}
+	if s.overrides != nil {
+		sub.overrides = s.overrides.resolve(path)
+	}
+
+	return sub, nil
+}
+
+// Snapshot returns a read-only view of c's current values. The snapshot is not affected by later reloads or
+// overrides, so it can be used to consistently read multiple values.
+func (c *AppConfig) Snapshot() *AppConfig {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return c.snapshot()
 }
 
 // GetString returns the string value stored under key.
@@ -230,35 +288,355 @@ func (c *AppConfig) GetDurationE(key string) (time.Duration, error) {
 	return n.GetDurationE()
 }
 
+// GetTime returns the time value stored under key.
+func (c *AppConfig) GetTime(key string) time.Time {
+	n, err := c.get(key)
+	if err != nil {
+		return time.Time{}
+	}
+	return n.GetTime()
+}
+
+// GetTimeE returns the time value stored under key or an error if the key is not defined or the value cannot
+// be converted to a time.Time (see Node.GetTimeE for supported formats).
+func (c *AppConfig) GetTimeE(key string) (time.Time, error) {
+	n, err := c.get(key)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return n.GetTimeE()
+}
+
+// GetURL returns the URL stored under key.
+func (c *AppConfig) GetURL(key string) *url.URL {
+	n, err := c.get(key)
+	if err != nil {
+		return nil
+	}
+	return n.GetURL()
+}
+
+// GetURLE returns the URL stored under key or an error if the key is not defined or the value cannot
+// be converted to an URL.
+func (c *AppConfig) GetURLE(key string) (*url.URL, error) {
+	n, err := c.get(key)
+	if err != nil {
+		return nil, err
+	}
+	return n.GetURLE()
+}
+
+// GetIP returns the IP address stored under key.
+func (c *AppConfig) GetIP(key string) net.IP {
+	n, err := c.get(key)
+	if err != nil {
+		return nil
+	}
+	return n.GetIP()
+}
+
+// GetIPE returns the IP address stored under key or an error if the key is not defined or the value cannot
+// be converted to an IP address.
+func (c *AppConfig) GetIPE(key string) (net.IP, error) {
+	n, err := c.get(key)
+	if err != nil {
+		return nil, err
+	}
+	return n.GetIPE()
+}
+
+// GetIPNet returns the network stored under key.
+func (c *AppConfig) GetIPNet(key string) *net.IPNet {
+	n, err := c.get(key)
+	if err != nil {
+		return nil
+	}
+	return n.GetIPNet()
+}
+
+// GetIPNetE returns the network stored under key or an error if the key is not defined or the value cannot
+// be converted to a network in CIDR notation.
+func (c *AppConfig) GetIPNetE(key string) (*net.IPNet, error) {
+	n, err := c.get(key)
+	if err != nil {
+		return nil, err
+	}
+	return n.GetIPNetE()
+}
+
+// GetByteSize returns the byte size stored under key.
+func (c *AppConfig) GetByteSize(key string) ByteSize {
+	n, err := c.get(key)
+	if err != nil {
+		return 0
+	}
+	return n.GetByteSize()
+}
+
+// GetByteSizeE returns the byte size stored under key or an error if the key is not defined or the value cannot
+// be converted to a ByteSize.
+func (c *AppConfig) GetByteSizeE(key string) (ByteSize, error) {
+	n, err := c.get(key)
+	if err != nil {
+		return 0, err
+	}
+	return n.GetByteSizeE()
+}
+
+// GetRegexp returns the regular expression stored under key.
+func (c *AppConfig) GetRegexp(key string) *regexp.Regexp {
+	n, err := c.get(key)
+	if err != nil {
+		return nil
+	}
+	return n.GetRegexp()
+}
+
+// GetRegexpE returns the regular expression stored under key or an error if the key is not defined or the
+// value cannot be converted to a regular expression.
+func (c *AppConfig) GetRegexpE(key string) (*regexp.Regexp, error) {
+	n, err := c.get(key)
+	if err != nil {
+		return nil, err
+	}
+	return n.GetRegexpE()
+}
+
+// GetStringSlice returns the list of strings stored under key.
+func (c *AppConfig) GetStringSlice(key string) []string {
+	v, _ := c.GetStringSliceE(key)
+	return v
+}
+
+// GetStringSliceE returns the list of strings stored under key or an error if the key is not defined or the
+// value cannot be converted. The value is converted the same way Bind converts a value of type []string.
+func (c *AppConfig) GetStringSliceE(key string) ([]string, error) {
+	return Get[[]string](c, key)
+}
+
+// GetIntSlice returns the list of ints stored under key.
+func (c *AppConfig) GetIntSlice(key string) []int {
+	v, _ := c.GetIntSliceE(key)
+	return v
+}
+
+// GetIntSliceE returns the list of ints stored under key or an error if the key is not defined or the value
+// cannot be converted. The value is converted the same way Bind converts a value of type []int.
+func (c *AppConfig) GetIntSliceE(key string) ([]int, error) {
+	return Get[[]int](c, key)
+}
+
+// GetStringMap returns the map stored under key.
+func (c *AppConfig) GetStringMap(key string) map[string]interface{} {
+	v, _ := c.GetStringMapE(key)
+	return v
+}
+
+// GetStringMapE returns the map stored under key or an error if the key is not defined or the value cannot
+// be converted. The value is converted the same way Bind converts a value of type map[string]interface{}.
+func (c *AppConfig) GetStringMapE(key string) (map[string]interface{}, error) {
+	return Get[map[string]interface{}](c, key)
+}
+
 // Bind binds the configuration to the data structure v and returns any error that occured during binding.
-// v must be a pointer to either a struct value or a map[string]interface{}. Other values are not supported
-// and are rejected by an error. See the README for an explanation of how to use and customize the binding.
-func (c *AppConfig) Bind(v interface{}) error {
-	return bind(c.n, v)
+// v must be a non-nil pointer to a value of a supported type, usually a struct or a map. Entries are added to
+// a non-nil map. opts customize the binding (see Strict and ReportUnused). See the README for an explanation
+// of how to use and customize the binding.
+func (c *AppConfig) Bind(v interface{}, opts ...BindOption) error {
+	r := c.redactor()
+	opts = append(opts[:len(opts):len(opts)], func(b *binder) {
+		b.secrets = r
+	})
+	return bind(c.root(), v, opts...)
+}
+
+// OnChange registers l to be called whenever c's values have been replaced by a successful reload. l
+// receives the configuration before and after the reload.
+func (c *AppConfig) OnChange(l func(old, new *AppConfig)) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.changeListeners = append(c.changeListeners, l)
+}
+
+// OnError registers l to be called whenever a reload triggered by Watch fails. The previously loaded values
+// stay active in this case.
+func (c *AppConfig) OnError(l func(error)) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.errorListeners = append(c.errorListeners, l)
+}
+
+// Reload executes the loaders c has been created with again and replaces c's values with the result. Values
+// set with Set are applied on top of the reloaded values. If any of the loaders fails, c keeps its current
+// values and the error is returned. On success all listeners registered with OnChange are notified.
+func (c *AppConfig) Reload() error {
+	if c.readOnly {
+		return ErrReadOnly
+	}
+
+	c.reloadLock.Lock()
+	defer c.reloadLock.Unlock()
+
+	layers, err := load(c.loaders)
+	if err != nil {
+		return err
+	}
+
+	return c.publish(layers, c.overrides)
+}
+
+// Set overrides the value stored under key with value at runtime. value is converted the same way as values
+// passed to Static; pass Unset to remove key. The override replaces any previous override of key and is kept
+// across reloads. All listeners registered with OnChange are notified.
+func (c *AppConfig) Set(key string, value interface{}) error {
+	if c.readOnly {
+		return ErrReadOnly
+	}
+
+	o, err := createNodeFromValue(value)
+	if err != nil {
+		return err
+	}
+	o.annotate(Source{Loader: "override"})
+
+	c.reloadLock.Lock()
+	defer c.reloadLock.Unlock()
+
+	overrides := NewNode("")
+	if c.overrides != nil {
+		overrides = c.overrides.Clone()
+	}
+	overrides.put(ParseKeyPath(key), o)
+
+	return c.publish(c.layers, overrides)
+}
+
+// publish merges layers and overrides into a new tree, publishes it as c's values and notifies all change
+// listeners. If the merged values cannot be interpolated, c keeps its current values and the error is
+// returned. Callers must hold c.reloadLock.
+func (c *AppConfig) publish(layers []*Node, overrides *Node) error {
+	n, err := c.merge(layers, overrides)
+	if err != nil {
+		return err
+	}
+
+	c.lock.Lock()
+	old := c.snapshot()
+	c.n = n
+	c.layers = layers
+	c.overrides = overrides
+	current := c.snapshot()
+	listeners := c.changeListeners
+	c.lock.Unlock()
+
+	for _, l := range listeners {
+		l(old, current)
+	}
+
+	return nil
+}
+
+// merge merges layers and overrides (which may be nil) into a new tree and resolves the references contained
+// in the merged values unless interpolation has been disabled.
+func (c *AppConfig) merge(layers []*Node, overrides *Node) (*Node, error) {
+	n := merge(layers, overrides, c.listMergeStrategies)
+	if c.noInterpolation {
+		return n, nil
+	}
+	if err := interpolate(n, c.redactor()); err != nil {
+		return nil, err
+	}
+	return n, nil
+}
+
+// snapshot creates a read-only copy of c. Callers must hold c.lock.
+func (c *AppConfig) snapshot() *AppConfig {
+	return &AppConfig{
+		n:         c.n,
+		layers:    c.layers,
+		overrides: c.overrides,
+		readOnly:  true,
+		path:      c.path,
+		secrets:   c.secrets,
+	}
+}
+
+func (c *AppConfig) root() *Node {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return c.n
 }
 
 func (c *AppConfig) get(key string) (*Node, error) {
-	n := c.n.resolve(ParseKeyPath(key))
+	n := c.root().resolve(ParseKeyPath(key))
 	if n == nil {
 		return nil, fmt.Errorf("%w: %s", ErrNoSuchKey, key)
 	}
 	return n, nil
 }
 
+// Option defines a function type to customize an AppConfig created with NewWithOptions.
+type Option func(*AppConfig)
+
 // New creates a new AppConfig using the given loaders. The loaders are executed in given order with values
 // from later loaders overwriting values from earlier ones (put most significant loaders last).
 func New(loaders ...Loader) (*AppConfig, error) {
+	return NewWithOptions(loaders)
+}
+
+// NewWithOptions works like New but applies opts to the created AppConfig before executing the loaders.
+func NewWithOptions(loaders []Loader, opts ...Option) (*AppConfig, error) {
 	c := &AppConfig{
-		n: NewNode(""),
+		loaders: loaders,
+		secrets: &secretKeys{},
+	}
+
+	for _, opt := range opts {
+		opt(c)
 	}
 
-	for _, l := range loaders {
+	layers, err := load(loaders)
+	if err != nil {
+		return nil, err
+	}
+
+	c.n, err = c.merge(layers, nil)
+	if err != nil {
+		return nil, err
+	}
+	c.layers = layers
+
+	return c, nil
+}
+
+// load executes all loaders in order and returns their results. Nodes without a loader name in their source
+// are annotated with the loader's position.
+func load(loaders []Loader) ([]*Node, error) {
+	layers := make([]*Node, 0, len(loaders))
+
+	for i, l := range loaders {
 		n, err := l.Load()
 		if err != nil {
 			return nil, err
 		}
-		c.n.OverwriteWith(n)
+		n.annotate(Source{Loader: fmt.Sprintf("loader #%d", i+1)})
+		layers = append(layers, n)
 	}
 
-	return c, nil
+	return layers, nil
+}
+
+// merge merges layers and overrides (which may be nil) into a new tree using rules to merge lists. The
+// returned tree does not share any Nodes with the given ones.
+func merge(layers []*Node, overrides *Node, rules []listMergeRule) *Node {
+	root := NewNode("")
+
+	for _, l := range layers {
+		root.overwriteWith(l, nil, rules)
+	}
+	if overrides != nil {
+		root.overwriteWith(overrides, nil, rules)
+	}
+
+	return root
 }
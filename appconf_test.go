@@ -1,6 +1,8 @@
 package appconf
 
 import (
+	"errors"
+	"sync"
 	"testing"
 	"time"
 
@@ -170,3 +172,142 @@ func TestAppConfig_Get(t *testing.T) {
 	assert.That(t, c.GetDuration("duration"), is.Equal(time.Second))
 	assert.That(t, c.GetDuration("durationnotfound"), is.Equal[time.Duration](0))
 }
+
+func TestAppConfig_Get_richTypes(t *testing.T) {
+	c, err := New(Static(map[string]interface{}{
+		"hosts":     []interface{}{"a", "b"},
+		"ports":     []interface{}{80, "443"},
+		"labels":    map[string]interface{}{"env": "prod"},
+		"timestamp": "2023-04-05T10:11:12+02:00",
+		"date":      "2023-04-05",
+		"endpoint":  "https://example.com/api",
+		"ip":        "10.0.0.1",
+		"network":   "192.168.0.0/16",
+		"buffer":    "512MiB",
+		"limit":     1024,
+		"pattern":   "^a+$",
+		"invalid":   "[",
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.That(t, c.GetStringSlice("hosts"), is.DeepEqual([]string{"a", "b"}))
+	assert.That(t, c.GetIntSlice("ports"), is.DeepEqual([]int{80, 443}))
+	assert.That(t, c.GetStringMap("labels"), is.DeepEqual(map[string]interface{}{"env": "prod"}))
+	assert.That(t, c.GetStringSlice("notfound"), is.DeepEqual[[]string](nil))
+
+	assert.That(t, c.GetTime("timestamp").Equal(time.Date(2023, 4, 5, 8, 11, 12, 0, time.UTC)), is.Equal(true))
+	assert.That(t, c.GetTime("date"), is.Equal(time.Date(2023, 4, 5, 0, 0, 0, 0, time.UTC)))
+	assert.That(t, c.GetTime("notfound").IsZero(), is.Equal(true))
+
+	assert.That(t, c.GetURL("endpoint").Host, is.Equal("example.com"))
+	assert.That(t, c.GetIP("ip").String(), is.Equal("10.0.0.1"))
+	assert.That(t, c.GetIPNet("network").String(), is.Equal("192.168.0.0/16"))
+	assert.That(t, c.GetByteSize("buffer"), is.Equal(512*MiB))
+	assert.That(t, c.GetByteSize("limit"), is.Equal(ByteSize(1024)))
+	assert.That(t, c.GetRegexp("pattern").MatchString("aaa"), is.Equal(true))
+
+	for _, get := range []func(string) error{
+		func(key string) error { _, err := c.GetTimeE(key); return err },
+		func(key string) error { _, err := c.GetIPE(key); return err },
+		func(key string) error { _, err := c.GetIPNetE(key); return err },
+		func(key string) error { _, err := c.GetByteSizeE(key); return err },
+		func(key string) error { _, err := c.GetRegexpE(key); return err },
+		func(key string) error { _, err := c.GetIntSliceE(key); return err },
+	} {
+		assert.That(t, get("invalid") != nil, is.Equal(true))
+	}
+}
+
+func TestAppConfig_Set(t *testing.T) {
+	c, err := New(Static(map[string]interface{}{
+		"web.address": "localhost:8080",
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var changes []string
+	c.OnChange(func(old, new *AppConfig) {
+		changes = append(changes, old.GetString("web.address")+" -> "+new.GetString("web.address"))
+	})
+
+	snapshot := c.Snapshot()
+
+	if err := c.Set("web.address", "localhost:9090"); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.That(t, c.GetString("web.address"), is.Equal("localhost:9090"))
+	assert.That(t, snapshot.GetString("web.address"), is.Equal("localhost:8080"))
+
+	if err := c.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	assert.That(t, c.GetString("web.address"), is.Equal("localhost:9090"))
+	assert.That(t, changes, is.DeepEqual([]string{
+		"localhost:8080 -> localhost:9090",
+		"localhost:9090 -> localhost:9090",
+	}))
+}
+
+func TestAppConfig_Snapshot_readOnly(t *testing.T) {
+	c, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := c.Snapshot()
+
+	assert.That(t, errors.Is(s.Set("foo", "bar"), ErrReadOnly), is.Equal(true))
+	assert.That(t, errors.Is(s.Reload(), ErrReadOnly), is.Equal(true))
+}
+
+func TestAppConfig_concurrentAccess(t *testing.T) {
+	c, err := New(Static(map[string]interface{}{
+		"counter": 0,
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				c.GetInt("counter")
+				c.Snapshot().HasKey("counter")
+			}
+		}()
+	}
+
+	for i := 0; i < 100; i++ {
+		if err := c.Set("counter", i); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	wg.Wait()
+
+	assert.That(t, c.GetInt("counter"), is.Equal(99))
+}
+
+func TestAppConfig_Set_unset(t *testing.T) {
+	c, err := New(Static(map[string]interface{}{
+		"web.address": "localhost:8080",
+		"web.timeout": "2s",
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.Set("web.address", Unset); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.That(t, c.HasKey("web.address"), is.Equal(false))
+	assert.That(t, c.HasKey("web.timeout"), is.Equal(true))
+}
@@ -4,80 +4,476 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"sort"
 	"strconv"
 	"strings"
-	"time"
 )
 
 const (
 	FieldTagKey            = "appconf"
 	FieldTagValueSeparator = ","
 	FieldTagIgnore         = "ignore"
+	FieldTagDefault        = "default"
+	FieldTagSquash         = "squash"
+	FieldTagSecret         = "secret"
+
+	// FieldTagDescriptionKey is the name of the struct tag containing a field's description used as the
+	// usage text of flags defined with DefineFlags.
+	FieldTagDescriptionKey = "description"
 )
 
 var (
 	// ErrInvalidBindingType is returned from binding when a value's type is not supported for binding.
 	ErrInvalidBindingType = errors.New("invalid binding type")
+
+	// ErrInvalidTag is returned from binding when a struct field's tag cannot be parsed.
+	ErrInvalidTag = errors.New("invalid tag")
+
+	// ErrUnusedKeys is returned from strict binding when the configuration contains keys not bound to any
+	// struct field.
+	ErrUnusedKeys = errors.New("unused keys")
+
+	// ErrInvalidValue is reported by all errors describing a config value that cannot be converted to the
+	// type of the value it is bound to.
+	ErrInvalidValue = errors.New("invalid value")
 )
 
-// bind binds to v values loaded from n. This is the entry point for binding. v must be a pointer to a struct
-// value or map[string]interface{}.
-func bind(n *Node, v interface{}) error {
+// BindError describes a problem binding a single value. This is either a config value that cannot be
+// converted to the type of the value it is bound to (reported as ErrInvalidValue), a field with an unsupported
+// type (ErrInvalidBindingType) or an invalid tag (ErrInvalidTag), a failed validation rule (ErrValidation with a
+// *ValidationError as the cause) or unused keys found by strict binding (ErrUnusedKeys).
+type BindError struct {
+	// Field is the Go field path of the value, i.e. DB.Port or Backends[0].Port.
+	Field string
+	// Key is the full key path of the value.
+	Key string
+	// Value is the raw config value or default that caused the error, if any.
+	Value string
+	// Type is the type of the value to bind.
+	Type reflect.Type
+	// Err is the cause of the error.
+	Err error
+	// Source is the origin of the value that caused the error. It is empty if the error is not caused by a
+	// config value.
+	Source Source
+
+	// conversion signals that the error has been caused by a failed conversion of Value.
+	conversion bool
+	// secret signals that the error has been caused by a secret value which must not be revealed.
+	secret bool
+}
+
+// newConversionError creates a BindError describing the failed conversion of n, which is stored under path,
+// to type t.
+func newConversionError(n *Node, t reflect.Type, path KeyPath, field string, err error) *BindError {
+	e := &BindError{
+		Field:      field,
+		Key:        path.Join(),
+		Value:      n.Value,
+		Type:       t,
+		Err:        err,
+		Source:     n.source,
+		conversion: true,
+	}
+	if n.secret {
+		e.redact()
+	}
+	return e
+}
+
+// newBindError creates a BindError caused by err for the value of type t bound to the key path.
+func newBindError(t reflect.Type, path KeyPath, field string, err error) *BindError {
+	return &BindError{
+		Field: field,
+		Key:   path.Join(),
+		Type:  t,
+		Err:   err,
+	}
+}
+
+// redact removes the value that caused e from e's message. The messages of failed validations are replaced
+// with the violated rule.
+func (e *BindError) redact() {
+	e.secret = true
+	if e.Value != "" {
+		e.Value = redacted
+	}
+
+	var valErr *ValidationError
+	if errors.As(e.Err, &valErr) && valErr.Rule != ruleRequired {
+		valErr.Message = "does not satisfy " + valErr.Rule
+	}
+}
+
+func (e *BindError) Error() string {
+	var msg string
+	var valErr *ValidationError
+
+	switch {
+	case e.conversion && e.secret:
+		msg = fmt.Sprintf("invalid %s %q", e.Type, e.Value)
+	case e.conversion:
+		cause := e.Err
+		var numErr *strconv.NumError
+		if errors.As(cause, &numErr) {
+			cause = numErr.Err
+		}
+		msg = fmt.Sprintf("invalid %s %q: %s", e.Type, e.Value, cause)
+	case errors.As(e.Err, &valErr):
+		msg = valErr.Message
+	default:
+		msg = e.Err.Error()
+	}
+
+	if e.Key != "" {
+		msg = e.Key + ": " + msg
+	}
+	if e.Field != "" {
+		msg += " (field " + e.Field + ")"
+	}
+	if l := e.Source.location(); l != "" {
+		msg = l + ": " + msg
+	}
+	return msg
+}
+
+func (e *BindError) Unwrap() error {
+	return e.Err
+}
+
+func (e *BindError) Is(target error) bool {
+	return e.conversion && target == ErrInvalidValue
+}
+
+// BindErrors collects all BindErrors reported while binding a value. errors.Is and errors.As test every
+// contained error.
+type BindErrors []*BindError
+
+func (e BindErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+func (e BindErrors) Is(target error) bool {
+	for _, err := range e {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+func (e BindErrors) As(target interface{}) bool {
+	for _, err := range e {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// BindOption defines a function type to customize binding.
+type BindOption func(*binder)
+
+// Strict creates a BindOption that makes binding fail with an ErrUnusedKeys error if the configuration
+// contains keys that are not bound to any struct field, i.e. due to a typo in a config file.
+func Strict() BindOption {
+	return func(b *binder) {
+		b.strict = true
+	}
+}
+
+// ReportUnused creates a BindOption that stores the keys not bound to any struct field in keys. Binding does
+// not fail due to unused keys.
+func ReportUnused(keys *[]string) BindOption {
+	return func(b *binder) {
+		b.unusedKeys = keys
+	}
+}
+
+// SlicePolicy defines how a configured list is bound to a slice field that already contains items before
+// binding, i.e. defaults set by the application.
+type SlicePolicy int
+
+const (
+	// SliceReplace replaces the existing items with the configured ones. This is the default policy.
+	SliceReplace SlicePolicy = iota
+	// SliceAppend appends the configured items to the existing ones.
+	SliceAppend
+	// SliceMergeByIndex merges each configured item into the existing item with the same index. Additional
+	// configured items are appended; existing items without a configured counterpart are kept.
+	SliceMergeByIndex
+)
+
+// WithSlicePolicy creates a BindOption that binds lists to non-empty slice fields using p.
+func WithSlicePolicy(p SlicePolicy) BindOption {
+	return func(b *binder) {
+		b.slicePolicy = p
+	}
+}
+
+// bind binds to v values loaded from n. This is the entry point for binding. v must be a non-nil pointer to a
+// value of any type supported for binding, usually a struct or a map. Entries bound to a non-nil map are added
+// to the map. All problems are reported together as BindErrors.
+func bind(n *Node, v interface{}, opts ...BindOption) error {
 	rv := reflect.ValueOf(v)
 
 	if rv.Kind() != reflect.Pointer || rv.IsNil() {
-		return fmt.Errorf("%w: cannot bind to non-pointer %s", ErrInvalidBindingType, reflect.TypeOf(v))
+		return BindErrors{
+			newBindError(reflect.TypeOf(v), nil, "", fmt.Errorf("%w: cannot bind to non-pointer %s", ErrInvalidBindingType, reflect.TypeOf(v))),
+		}
 	}
 
-	switch reflect.Indirect(rv).Kind() {
-	case reflect.Struct:
-		return bindStruct(n, rv)
-	case reflect.Map:
-		mptr, ok := v.(*map[string]interface{})
-		if !ok {
-			return fmt.Errorf("%w: invalid map: %t", ErrInvalidBindingType, v)
+	b := binder{
+		used: make(map[string]struct{}),
+	}
+	for _, opt := range opts {
+		opt(&b)
+	}
+
+	t := rv.Elem().Type()
+	switch {
+	case t.Kind() == reflect.Struct && isNested(t):
+		b.bindStruct(n, rv, nil, "")
+	case t.Kind() == reflect.Map && t.Key().Kind() == reflect.String && isNested(t) && !rv.Elem().IsNil():
+		b.bindMap(n, rv.Elem(), nil, "")
+	default:
+		val, err := b.convertNode(n, t, rv.Elem(), nil, "")
+		if err != nil {
+			b.record(err)
+		} else if val.IsValid() {
+			rv.Elem().Set(val)
 		}
+	}
+
+	b.checkUnused(n)
+
+	if len(b.errs) > 0 {
+		b.redactSecretErrors()
+		return b.errs
+	}
+	return nil
+}
+
+// binder implements binding of a Node tree to a Go value. It collects the errors reported during binding.
+type binder struct {
+	errs        BindErrors
+	strict      bool
+	unusedKeys  *[]string
+	slicePolicy SlicePolicy
+	used        map[string]struct{}
+	secrets     redactor
+}
+
+// record adds err, which usually is a *BindError, to the errors reported by b.
+func (b *binder) record(err error) {
+	var bindErr *BindError
+	if !errors.As(err, &bindErr) {
+		bindErr = &BindError{Err: err}
+	}
+	b.errs = append(b.errs, bindErr)
+}
+
+// use marks the key identified by path (including all nested keys) as used.
+func (b *binder) use(path KeyPath) {
+	b.used[path.Join()] = struct{}{}
+}
+
+// checkUnused determines the keys from n not marked as used. It reports them as configured by the
+// BindOptions.
+func (b *binder) checkUnused(n *Node) {
+	if !b.strict && b.unusedKeys == nil {
+		return
+	}
+
+	var unused []string
+	b.collectUnused(n, nil, &unused)
+	sort.Strings(unused)
+
+	if b.unusedKeys != nil {
+		*b.unusedKeys = unused
+	}
+
+	if b.strict && len(unused) > 0 {
+		b.record(fmt.Errorf("%w: %s", ErrUnusedKeys, strings.Join(unused, ", ")))
+	}
+}
+
+// collectUnused adds the key paths of all leaf nodes of n (which is stored under path) not marked as used
+// to unused.
+func (b *binder) collectUnused(n *Node, path KeyPath, unused *[]string) {
+	if _, ok := b.used[path.Join()]; ok && len(path) > 0 {
+		return
+	}
 
-		if *mptr == nil {
-			m := make(map[string]interface{})
-			*mptr = m
+	if len(n.Children) == 0 {
+		if len(path) > 0 {
+			*unused = append(*unused, path.Join())
 		}
-		return bindMap(n, *mptr)
-	default:
-		return fmt.Errorf("%w: cannot bind to value of type %q", ErrInvalidBindingType, rv.Type())
+		return
+	}
+
+	for k, c := range n.Children {
+		b.collectUnused(c, append(path[:len(path):len(path)], k), unused)
 	}
 }
 
-// bindStruct binds the struct fields of the value described by rv to config values read from n.
-func bindStruct(n *Node, rv reflect.Value) error {
+// bindStruct binds the struct fields of the value described by rv to config values read from n. path is
+// the key path of n and field the Go field path of rv.
+func (b *binder) bindStruct(n *Node, rv reflect.Value, path KeyPath, field string) {
 	rt := reflect.Indirect(rv).Type()
 
 	numFields := rt.NumField()
 
 	for i := 0; i < numFields; i++ {
 		f := rt.Field(i)
-		opts := determineBindOpts(f)
+		fieldName := joinFieldPath(field, f.Name)
+
+		opts, err := determineBindOpts(f)
+		if err != nil {
+			b.record(newBindError(f.Type, joinKeyPath(path, opts.key), fieldName, fmt.Errorf("%w: %s", ErrInvalidTag, err)))
+			continue
+		}
 
 		if opts.ignore {
 			continue
 		}
 
-		v, err := resolveReflectValue(n, f.Type, opts)
+		if opts.squash {
+			b.bindSquashed(n, rv.Elem().Field(i), path, fieldName)
+			continue
+		}
+
+		if !f.IsExported() {
+			continue
+		}
+
+		fieldPath := joinKeyPath(path, opts.key)
+
+		if !opts.secret && !isSecretType(f.Type) && !b.secrets.isSecretKey(fieldPath) {
+			b.bindField(n, rv.Elem().Field(i), opts, path, fieldName)
+			continue
+		}
+
+		b.secrets.mark(fieldPath)
+		start := len(b.errs)
+		b.bindField(n, rv.Elem().Field(i), opts, path, fieldName)
+		for _, e := range b.errs[start:] {
+			e.redact()
+		}
+	}
+}
+
+// bindField binds the struct field fv to the config value read from n using opts. path is the key path of
+// n and field the Go field path of fv.
+func (b *binder) bindField(n *Node, fv reflect.Value, opts structFieldBindOpts, path KeyPath, field string) {
+	fieldPath := joinKeyPath(path, opts.key)
+
+	v, err := b.resolveReflectValue(n, fv.Type(), fv, opts, path, field)
+	if err != nil {
+		b.record(err)
+		return
+	}
+
+	if !v.IsValid() && opts.hasDefault {
+		v, err = b.resolveDefault(fv.Type(), opts.defaultValue, fieldPath)
 		if err != nil {
-			return fmt.Errorf("%w: struct field %s: %s", ErrInvalidBindingType, f.Name, err)
+			bindErr := newBindError(fv.Type(), fieldPath, field, fmt.Errorf("%w: invalid default %q: %s", ErrInvalidTag, opts.defaultValue, err))
+			bindErr.Value = opts.defaultValue
+			b.record(bindErr)
+			return
 		}
+	}
+
+	if v != (reflect.Value{}) {
+		fv.Set(v)
+	}
+
+	var src Source
+	if vn := n.resolve(ParseKeyPath(opts.key)); vn != nil {
+		src = vn.source
+	}
+	b.validate(fieldPath, field, fv, v.IsValid(), src, opts.rules)
 
-		if v != (reflect.Value{}) {
-			rv.Elem().Field(i).Set(v)
+	if !v.IsValid() && fv.Kind() == reflect.Struct && isNested(fv.Type()) {
+		// Bind the nested struct to an empty tree to apply the defaults of its fields and check for
+		// required fields.
+		b.bindStruct(NewNode(""), fv.Addr(), fieldPath, field)
+	}
+}
+
+// bindSquashed binds the fields of the struct (or pointer to struct) fv to config values read from n, just as
+// if they were declared in the struct containing fv. Nil pointers are allocated. path is the key path of n and
+// field the Go field path of fv.
+func (b *binder) bindSquashed(n *Node, fv reflect.Value, path KeyPath, field string) {
+	switch {
+	case fv.Kind() == reflect.Struct:
+		b.bindStruct(n, fv.Addr(), path, field)
+	case fv.Kind() == reflect.Pointer && fv.Type().Elem().Kind() == reflect.Struct && fv.CanSet():
+		if fv.IsNil() {
+			fv.Set(reflect.New(fv.Type().Elem()))
 		}
+		b.bindStruct(n, fv, path, field)
+	default:
+		b.record(newBindError(fv.Type(), path, field, fmt.Errorf("%w: %s requires a struct or an exported pointer to struct", ErrInvalidTag, FieldTagSquash)))
 	}
+}
 
-	return nil
+// isNested reports whether values of type t are bound from nested keys (structs, slices, arrays, maps and
+// pointers to these) rather than from a single config value.
+func isNested(t reflect.Type) bool {
+	if _, ok := decoderFor(t); ok {
+		return false
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		return isNested(t.Elem())
+	case reflect.Struct, reflect.Slice, reflect.Array, reflect.Map:
+		return true
+	default:
+		return false
+	}
+}
+
+// redactSecretErrors redacts the recorded errors caused by values stored under keys marked as secret. This
+// covers keys nested in maps and slices, which are not redacted while binding struct fields.
+func (b *binder) redactSecretErrors() {
+	for _, e := range b.errs {
+		if b.secrets.isSecretKey(ParseKeyPath(e.Key)) {
+			e.redact()
+		}
+	}
+}
+
+// bindValue converts n to a value of type t using the same conversions applied when binding struct fields.
+// path is the key path of n. Errors caused by values r considers secret are redacted.
+func bindValue(n *Node, t reflect.Type, path KeyPath, r redactor) (reflect.Value, error) {
+	b := binder{
+		used:    make(map[string]struct{}),
+		secrets: r,
+	}
+
+	v, err := b.convertNode(n, t, reflect.Value{}, path, "")
+	if err != nil {
+		b.record(err)
+	}
+	if len(b.errs) > 0 {
+		b.redactSecretErrors()
+		return reflect.Value{}, b.errs
+	}
+
+	return v, nil
 }
 
 // resolveReflectValue resolves the config value described by opts and loaded from n. It is converted to a
-// reflect.Value with respect to t. t can be either a time.Duration, a struct, a slice or a primitive value.
-func resolveReflectValue(n *Node, t reflect.Type, opts structFieldBindOpts) (reflect.Value, error) {
+// reflect.Value with respect to t, which can be any type supported for binding.
+// cur is the value currently stored in the bound field (if any); nested values are merged into a copy of cur.
+// path is the key path of n and field the Go field path of the value to bind.
+func (b *binder) resolveReflectValue(n *Node, t reflect.Type, cur reflect.Value, opts structFieldBindOpts, path KeyPath, field string) (reflect.Value, error) {
 	keyPath := ParseKeyPath(opts.key)
 
 	n = n.resolve(keyPath)
@@ -85,24 +481,198 @@ func resolveReflectValue(n *Node, t reflect.Type, opts structFieldBindOpts) (ref
 		return reflect.Value{}, nil
 	}
 
-	if t == reflect.TypeOf(time.Second) {
-		return reflect.ValueOf(n.GetDuration()), nil
+	if n.kind == KindNull {
+		b.use(joinKeyPath(path, opts.key))
+		return reflect.Value{}, nil
+	}
+
+	return b.convertNode(n, t, cur, joinKeyPath(path, opts.key), field)
+}
+
+// resolveDefault converts the default value def given in a struct tag to a reflect.Value of type t. Defaults
+// for slices are given as comma separated values. path is the key path the default is used for.
+func (b *binder) resolveDefault(t reflect.Type, def string, path KeyPath) (reflect.Value, error) {
+	isList := t.Kind() == reflect.Slice || t.Kind() == reflect.Array
+	if isNested(t) && !isList {
+		return reflect.Value{}, fmt.Errorf("defaults not supported for %s", t)
+	}
+
+	n := NewNode(def)
+	if isNested(t) {
+		n = NewListNode()
+		if def != "" {
+			for i, item := range strings.Split(def, FieldTagValueSeparator) {
+				n.Children[indexKey(i)] = NewNode(strings.TrimSpace(item))
+			}
+		}
+	}
+
+	// Use a separate binder to report invalid defaults as an error instead of collecting them.
+	d := binder{used: b.used}
+	v, err := d.convertNode(n, t, reflect.Value{}, path, "")
+	if err != nil {
+		d.record(err)
 	}
+	if len(d.errs) > 0 {
+		return reflect.Value{}, d.errs[0].Err
+	}
+
+	return v, nil
+}
+
+// convertScalar converts n's value to a value of the scalar type t. It reports an error if the value cannot be
+// converted or overflows t.
+func convertScalar(n *Node, t reflect.Type) (reflect.Value, error) {
+	v := reflect.New(t).Elem()
 
 	switch t.Kind() {
+	case reflect.String:
+		s, err := n.GetStringE()
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		v.SetString(s)
+
+	case reflect.Bool:
+		b, err := n.GetBoolE()
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		v.SetBool(b)
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := n.GetInt64E()
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		if v.OverflowInt(i) {
+			return reflect.Value{}, n.numError("ParseInt", strconv.ErrRange)
+		}
+		v.SetInt(i)
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := n.GetUint64E()
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		if v.OverflowUint(u) {
+			return reflect.Value{}, n.numError("ParseUint", strconv.ErrRange)
+		}
+		v.SetUint(u)
+
+	case reflect.Float32, reflect.Float64:
+		f, err := n.GetFloat64E()
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		if v.OverflowFloat(f) {
+			return reflect.Value{}, n.numError("ParseFloat", strconv.ErrRange)
+		}
+		v.SetFloat(f)
+
+	case reflect.Complex64, reflect.Complex128:
+		c, err := n.GetComplex128E()
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		if v.OverflowComplex(c) {
+			return reflect.Value{}, n.numError("ParseComplex", strconv.ErrRange)
+		}
+		v.SetComplex(c)
+
+	default:
+		return reflect.Value{}, fmt.Errorf("%w: unsupported kind: %s", ErrNotAScalar, t.Kind())
+	}
+
+	return v, nil
+}
+
+// convertNode converts n to a reflect.Value of type t. Structs, maps, arrays and pointers to these are merged
+// into a copy of cur which is the value currently stored in the bound field (if valid); slices are handled
+// according to the binder's SlicePolicy. path is the key path of n and field the Go field path of the value.
+// Problems binding n itself are returned as a *BindError; problems binding nested values are recorded in b.
+func (b *binder) convertNode(n *Node, t reflect.Type, cur reflect.Value, path KeyPath, field string) (reflect.Value, error) {
+	// Nested values mark the keys used by their fields or items.
+	if !isNested(t) || len(n.Children) == 0 {
+		b.use(path)
+	}
+
+	if decode, ok := decoderFor(t); ok {
+		v, err := decode(n)
+		if err != nil {
+			return reflect.Value{}, newConversionError(n, t, path, field, err)
+		}
+		return v, nil
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		var curElem reflect.Value
+		if cur.IsValid() && !cur.IsNil() {
+			curElem = cur.Elem()
+		}
+		v, err := b.convertNode(n, t.Elem(), curElem, path, field)
+		if err != nil || !v.IsValid() {
+			return reflect.Value{}, err
+		}
+		ptr := reflect.New(t.Elem())
+		ptr.Elem().Set(v)
+		return ptr, nil
+
+	case reflect.Interface:
+		if t.NumMethod() != 0 {
+			return reflect.Value{}, newBindError(t, path, field, fmt.Errorf("%w: type not supported: %s", ErrInvalidBindingType, t))
+		}
+		v := reflect.New(t).Elem()
+		if i := nodeToInterface(n); i != nil {
+			v.Set(reflect.ValueOf(i))
+		}
+		return v, nil
+
 	case reflect.Struct:
 		ptr := reflect.New(t)
-		if err := bindStruct(n, ptr); err != nil {
-			return reflect.Value{}, err
+		if cur.IsValid() {
+			ptr.Elem().Set(cur)
 		}
+		b.bindStruct(n, ptr, path, field)
 		return ptr.Elem(), nil
 
 	case reflect.Slice:
-		v := reflect.MakeSlice(t, 0, 10)
-		v, err := bindSlice(n, v)
-		if err != nil {
-			return reflect.Value{}, nil
+		if len(n.Children) == 0 && n.kind != KindList && n.Value != "" {
+			return reflect.Value{}, newConversionError(n, t, path, field, ErrNotAList)
+		}
+		return b.bindSlice(n, t, cur, path, field), nil
+
+	case reflect.Array:
+		if len(n.Children) == 0 && n.kind != KindList && n.Value != "" {
+			return reflect.Value{}, newConversionError(n, t, path, field, ErrNotAList)
+		}
+		if l := listLength(n); l > t.Len() {
+			return reflect.Value{}, newConversionError(n, t, path, field,
+				fmt.Errorf("%d items exceed array length %d", l, t.Len()))
+		}
+		v := reflect.New(t).Elem()
+		if cur.IsValid() {
+			v.Set(cur)
+		}
+		b.bindArray(n, v, path, field)
+		return v, nil
+
+	case reflect.Map:
+		if t.Key().Kind() != reflect.String {
+			return reflect.Value{}, newBindError(t, path, field, fmt.Errorf("%w: unsupported map key type: %s", ErrInvalidBindingType, t))
+		}
+		if len(n.Children) == 0 && n.Value != "" {
+			return reflect.Value{}, newConversionError(n, t, path, field, ErrNotAMap)
+		}
+		v := reflect.MakeMapWithSize(t, len(n.Children))
+		if cur.IsValid() {
+			iter := cur.MapRange()
+			for iter.Next() {
+				v.SetMapIndex(iter.Key(), iter.Value())
+			}
 		}
+		b.bindMap(n, v, path, field)
 		return v, nil
 
 	case reflect.String,
@@ -121,109 +691,261 @@ func resolveReflectValue(n *Node, t reflect.Type, opts structFieldBindOpts) (ref
 		reflect.Complex128,
 		reflect.Float32,
 		reflect.Float64:
-		s, err := resolveScalar(n, t.Kind())
+		v, err := convertScalar(n, t)
 		if err != nil {
-			return reflect.Value{}, err
+			return reflect.Value{}, newConversionError(n, t, path, field, err)
 		}
-		return reflect.ValueOf(s), nil
+		return v, nil
 	default:
-		return reflect.Value{}, fmt.Errorf("%w: type not supported: %s", ErrInvalidBindingType, t)
+		return reflect.Value{}, newBindError(t, path, field, fmt.Errorf("%w: type not supported: %s", ErrInvalidBindingType, t))
 	}
 }
 
-func resolveScalar(n *Node, rk reflect.Kind) (interface{}, error) {
-	switch rk {
-	case reflect.String:
-		return n.GetString(), nil
-	case reflect.Bool:
-		return n.GetBool(), nil
-	case reflect.Int:
-		return n.GetInt(), nil
-	case reflect.Int8:
-		return int8(n.GetInt()), nil
-	case reflect.Int16:
-		return int16(n.GetInt()), nil
-	case reflect.Int32:
-		return int32(n.GetInt64()), nil
-	case reflect.Int64:
-		return n.GetInt64(), nil
-	case reflect.Uint:
-		return n.GetUint(), nil
-	case reflect.Uint8:
-		return uint8(n.GetUint()), nil
-	case reflect.Uint16:
-		return uint16(n.GetUint()), nil
-	case reflect.Uint32:
-		return uint32(n.GetUint64()), nil
-	case reflect.Uint64:
-		return n.GetUint64(), nil
-	case reflect.Complex64:
-		return complex64(n.GetComplex128()), nil
-	case reflect.Complex128:
-		return n.GetComplex128(), nil
-	case reflect.Float32:
-		return n.GetFloat32(), nil
-	case reflect.Float64:
-		return n.GetFloat64(), nil
-	default:
-		return nil, fmt.Errorf("%w: unsupported kind: %s", ErrNotAScalar, rk)
+// bindSlice creates a slice of type t from the items of the list n. cur is the slice currently stored in
+// the bound field (if valid); it is combined with the items according to b's SlicePolicy. Items that cannot be
+// converted are recorded in b and bound as zero values. path is the key path of n and field the Go field
+// path of the slice.
+func (b *binder) bindSlice(n *Node, t reflect.Type, cur reflect.Value, path KeyPath, field string) reflect.Value {
+	var existing int
+	if cur.IsValid() && b.slicePolicy != SliceReplace {
+		existing = cur.Len()
+	}
+
+	length := listLength(n)
+
+	rv := reflect.MakeSlice(t, 0, existing+length)
+	if b.slicePolicy == SliceAppend && cur.IsValid() {
+		rv = reflect.AppendSlice(rv, cur.Slice(0, existing))
 	}
-}
 
-func bindSlice(n *Node, rv reflect.Value) (reflect.Value, error) {
-	for idx := 0; idx < len(n.Children); idx++ {
-		v, err := resolveReflectValue(n, rv.Type().Elem(), structFieldBindOpts{key: strconv.Itoa(idx)})
+	for idx := 0; idx < length; idx++ {
+		var curItem reflect.Value
+		if b.slicePolicy == SliceMergeByIndex && idx < existing {
+			curItem = cur.Index(idx)
+		}
+
+		v, err := b.resolveReflectValue(n, t.Elem(), curItem, structFieldBindOpts{key: strconv.Itoa(idx)}, path, fmt.Sprintf("%s[%d]", field, idx))
 		if err != nil {
-			if errors.Is(err, ErrNoSuchKey) {
-				return rv, nil
-			}
-			return rv, err
+			b.record(err)
+		}
+		if !v.IsValid() {
+			v = reflect.Zero(t.Elem())
 		}
 		rv = reflect.Append(rv, v)
 	}
 
-	return rv, nil
+	if b.slicePolicy == SliceMergeByIndex && existing > length {
+		rv = reflect.AppendSlice(rv, cur.Slice(length, existing))
+	}
+
+	return rv
 }
 
-func bindMap(n *Node, m map[string]interface{}) error {
-	if len(n.Children) == 0 {
-		return nil
+// listLength returns the number of items bound from the list n: one more than the largest list index used as
+// a key of n. n may contain gaps, i.e. when single items have been set by index on the command line; the
+// missing items are bound as zero values.
+func listLength(n *Node) int {
+	length := 0
+	for k := range n.Children {
+		if i, err := strconv.Atoi(string(k)); err == nil && i >= length {
+			length = i + 1
+		}
 	}
+	return length
+}
 
-	for k, c := range n.Children {
-		if len(c.Children) == 0 {
-			m[string(k)] = c.Value
-		} else {
-			cm := make(map[string]interface{})
-			bindMap(c, cm)
-			m[string(k)] = cm
+// bindArray binds the items of the list n to the elements of the array rv. Elements without an item keep
+// their value. Items that cannot be converted are recorded in b. path is the key path of n and field the Go
+// field path of rv.
+func (b *binder) bindArray(n *Node, rv reflect.Value, path KeyPath, field string) {
+	for idx := 0; idx < listLength(n); idx++ {
+		v, err := b.resolveReflectValue(n, rv.Type().Elem(), rv.Index(idx), structFieldBindOpts{key: strconv.Itoa(idx)}, path, fmt.Sprintf("%s[%d]", field, idx))
+		if err != nil {
+			b.record(err)
+			continue
+		}
+		if v.IsValid() {
+			rv.Index(idx).Set(v)
 		}
 	}
+}
 
-	return nil
+// bindMap puts the children of n into the map rv using their keys. Values that cannot be converted are
+// recorded in b and left out; null values are put as zero values. path is the key path of n and field the Go
+// field path of rv.
+func (b *binder) bindMap(n *Node, rv reflect.Value, path KeyPath, field string) {
+	t := rv.Type()
+
+	keys := make([]string, 0, len(n.Children))
+	for k := range n.Children {
+		keys = append(keys, string(k))
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		key := reflect.ValueOf(k).Convert(t.Key())
+
+		v, err := b.resolveReflectValue(n, t.Elem(), rv.MapIndex(key), structFieldBindOpts{key: k}, path, fmt.Sprintf("%s[%q]", field, k))
+		if err != nil {
+			b.record(err)
+			continue
+		}
+		if !v.IsValid() {
+			v = reflect.Zero(t.Elem())
+		}
+		rv.SetMapIndex(key, v)
+	}
+}
+
+// nodeToInterface converts n to a generic value. Maps are converted to map[string]interface{}, lists to
+// []interface{} and scalar values to the value returned from n.Interface.
+func nodeToInterface(n *Node) interface{} {
+	switch n.Kind() {
+	case KindMap:
+		m := make(map[string]interface{}, len(n.Children))
+		for k, c := range n.Children {
+			m[string(k)] = nodeToInterface(c)
+		}
+		return m
+	case KindList:
+		items := n.Items()
+		l := make([]interface{}, len(items))
+		for i, item := range items {
+			l[i] = nodeToInterface(item)
+		}
+		return l
+	default:
+		return n.Interface()
+	}
+}
+
+// joinFieldPath returns the Go field path formed by appending name to field.
+func joinFieldPath(field, name string) string {
+	if field == "" {
+		return name
+	}
+	return field + "." + name
+}
+
+// joinKeyPath returns the key path formed by appending key to path.
+func joinKeyPath(path KeyPath, key string) KeyPath {
+	return append(path[:len(path):len(path)], ParseKeyPath(key)...)
 }
 
 type structFieldBindOpts struct {
-	key    string
-	ignore bool
+	key          string
+	ignore       bool
+	squash       bool
+	secret       bool
+	hasDefault   bool
+	defaultValue string
+	rules        []validationRule
 }
 
-func determineBindOpts(f reflect.StructField) structFieldBindOpts {
+func determineBindOpts(f reflect.StructField) (structFieldBindOpts, error) {
 	opts := structFieldBindOpts{
 		key: strings.ToLower(f.Name),
 	}
 
 	t := f.Tag.Get(FieldTagKey)
-	parts := strings.Split(t, FieldTagValueSeparator)
+	parts := splitTagOptions(t, defaultAcceptsSeparator(f.Type))
 
 	for i, p := range parts {
 		p = strings.TrimSpace(p)
-		if i == 0 && len(p) > 0 {
-			opts.key = parts[0]
-		} else if i > 0 && p == FieldTagIgnore {
+		if i == 0 {
+			if len(p) > 0 {
+				opts.key = parts[0]
+			}
+			continue
+		}
+
+		if p == FieldTagIgnore {
 			opts.ignore = true
+			continue
+		}
+
+		if p == FieldTagSquash {
+			opts.squash = true
+			continue
+		}
+
+		if p == FieldTagSecret {
+			opts.secret = true
+			continue
+		}
+
+		name, arg, _ := strings.Cut(p, "=")
+		if name == FieldTagDefault {
+			opts.hasDefault = true
+			opts.defaultValue = arg
+			continue
+		}
+
+		if _, ok := validationRuleNames[name]; ok {
+			r, err := newValidationRule(name, arg, f.Type)
+			if err != nil {
+				return opts, err
+			}
+			opts.rules = append(opts.rules, r)
+			continue
+		}
+
+		if p != "" {
+			return opts, fmt.Errorf("unknown option %q", p)
+		}
+	}
+
+	return opts, nil
+}
+
+// splitTagOptions splits the tag t into its comma separated options. Since the arguments of regexp and
+// default (if defaultCommas is set) may contain commas, a part following one of these options that does not
+// start with the name of a known option is considered to be part of the option's argument.
+func splitTagOptions(t string, defaultCommas bool) []string {
+	parts := strings.Split(t, FieldTagValueSeparator)
+	opts := make([]string, 0, len(parts))
+
+	for i, p := range parts {
+		if i < 2 || isTagOption(p) || !acceptsSeparator(opts[len(opts)-1], defaultCommas) {
+			opts = append(opts, p)
+			continue
 		}
+		opts[len(opts)-1] += FieldTagValueSeparator + p
 	}
 
 	return opts
 }
+
+// acceptsSeparator reports whether the argument of the tag option p may contain commas.
+func acceptsSeparator(p string, defaultCommas bool) bool {
+	name, _, _ := strings.Cut(strings.TrimSpace(p), "=")
+	return name == ruleRegexp || (name == FieldTagDefault && defaultCommas)
+}
+
+// defaultAcceptsSeparator reports whether a default for a field of type t may contain commas. This is not the
+// case for booleans and numbers.
+func defaultAcceptsSeparator(t reflect.Type) bool {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128:
+		return false
+	default:
+		return true
+	}
+}
+
+// isTagOption reports whether p starts with the name of a known tag option.
+func isTagOption(p string) bool {
+	name, _, _ := strings.Cut(strings.TrimSpace(p), "=")
+	if name == FieldTagIgnore || name == FieldTagDefault || name == FieldTagSquash || name == FieldTagSecret {
+		return true
+	}
+	_, ok := validationRuleNames[name]
+	return ok
+}
@@ -1,6 +1,14 @@
 package appconf
 
 import (
+	"errors"
+	"net"
+	"net/url"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
@@ -166,3 +174,801 @@ func TestAppConfig_Bind_scalars(t *testing.T) {
 		}),
 	)
 }
+
+func TestAppConfig_Bind_bindErrors(t *testing.T) {
+	type (
+		DB struct {
+			Port    int    `appconf:",max=1000"`
+			Host    string `appconf:",min=x"`
+			Channel chan int
+		}
+
+		Config struct {
+			DB      DB
+			Timeout time.Duration `appconf:",default=5"`
+			Debug   bool
+		}
+	)
+
+	c, err := New(Static(map[string]interface{}{
+		"db.port":    3306,
+		"db.channel": "c",
+		"debug":      "maybe",
+		"db.hots":    "localhost",
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var config Config
+	err = c.Bind(&config, Strict())
+
+	var bindErrs BindErrors
+	if !errors.As(err, &bindErrs) {
+		t.Fatalf("expected BindErrors but got %v", err)
+	}
+
+	type result struct {
+		Field, Key, Value string
+		Type              reflect.Type
+		Cause             error
+	}
+	got := make([]result, len(bindErrs))
+	for i, e := range bindErrs {
+		got[i] = result{e.Field, e.Key, e.Value, e.Type, e.Err}
+	}
+
+	causes := []error{ErrValidation, ErrInvalidTag, ErrInvalidBindingType, ErrInvalidTag, ErrInvalidValue, ErrUnusedKeys}
+	assert.That(t, len(bindErrs), is.Equal(len(causes)))
+	for i, cause := range causes {
+		if !errors.Is(bindErrs[i], cause) {
+			t.Errorf("expected error %d (%v) to be %v", i, bindErrs[i], cause)
+		}
+	}
+
+	assert.That(t, got[0].Field, is.Equal("DB.Port"))
+	assert.That(t, got[0].Key, is.Equal("db.port"))
+	assert.That(t, got[1].Field, is.Equal("DB.Host"))
+	assert.That(t, got[2].Field, is.Equal("DB.Channel"))
+	assert.That(t, got[2].Type.String(), is.Equal("chan int"))
+	assert.That(t, got[3].Field, is.Equal("Timeout"))
+	assert.That(t, got[3].Value, is.Equal("5"))
+	assert.That(t, got[4].Field, is.Equal("Debug"))
+	assert.That(t, got[4].Value, is.Equal("maybe"))
+	assert.That(t, got[4].Type.String(), is.Equal("bool"))
+
+	var bindErr *BindError
+	assert.That(t, errors.As(err, &bindErr), is.Equal(true))
+	assert.That(t, bindErr.Field, is.Equal("DB.Port"))
+
+	var valErr *ValidationError
+	assert.That(t, errors.As(err, &valErr), is.Equal(true))
+	assert.That(t, valErr.Rule, is.Equal("max=1000"))
+
+	assert.That(t, err.Error(), is.Equal(strings.Join([]string{
+		"db.port: value 3306 is greater than 1000 (field DB.Port)",
+		"db.host: invalid tag: invalid argument for min: \"x\" (field DB.Host)",
+		"db.channel: invalid binding type: type not supported: chan int (field DB.Channel)",
+		"timeout: invalid tag: invalid default \"5\": time: missing unit in duration \"5\" (field Timeout)",
+		"debug: invalid bool \"maybe\": invalid syntax (field Debug)",
+		"unused keys: db.hots",
+	}, "; ")))
+}
+
+func TestAppConfig_Bind_sourcePositions(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "config.yaml")
+	writeFile(t, filename, `db:
+  host: localhost
+  port: abc
+  pool: 500
+`)
+
+	c, err := New(YAMLFile(filename, true))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var config struct {
+		DB struct {
+			Host string
+			Port int
+			Pool int `appconf:",max=100"`
+		}
+	}
+	err = c.Bind(&config)
+
+	var bindErrs BindErrors
+	if !errors.As(err, &bindErrs) {
+		t.Fatalf("expected BindErrors but got %v", err)
+	}
+	assert.That(t, len(bindErrs), is.Equal(2))
+	assert.That(t, bindErrs[0].Source, is.Equal(Source{Loader: "yaml", File: filename, Line: 3, Column: 9}))
+	assert.That(t, bindErrs[0].Error(), is.Equal(filename+`:3:9: db.port: invalid int "abc": invalid syntax (field DB.Port)`))
+	assert.That(t, bindErrs[1].Error(), is.Equal(filename+`:4:9: db.pool: value 500 is greater than 100 (field DB.Pool)`))
+}
+
+func TestAppConfig_Bind_namedTypes(t *testing.T) {
+	type (
+		Level string
+		Port  uint16
+
+		Config struct {
+			Level Level
+			Port  Port
+		}
+	)
+
+	c, err := New(Static(map[string]interface{}{
+		"level": "debug",
+		"port":  8080,
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var config Config
+	if err := c.Bind(&config); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.That(t, config, is.Equal(Config{Level: "debug", Port: 8080}))
+}
+
+func TestAppConfig_Bind_conversionErrors(t *testing.T) {
+	type (
+		DB struct {
+			Host string
+			Port int
+		}
+
+		Backend struct {
+			Port uint8
+		}
+
+		Config struct {
+			DB       DB
+			Timeout  time.Duration
+			Debug    bool
+			Backends []Backend
+			Ports    []int
+		}
+	)
+
+	c, err := New(LoaderFunc(func() (*Node, error) {
+		return JSON(strings.NewReader(`{
+			"db": {"host": "localhost", "port": "abc"},
+			"timeout": "5",
+			"debug": true,
+			"backends": [{"port": 80}, {"port": 300}],
+			"ports": [80, "http"]
+		}`))
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var config Config
+	err = c.Bind(&config)
+
+	assert.That(t, errors.Is(err, ErrInvalidValue), is.Equal(true))
+
+	var bindErrs BindErrors
+	if !errors.As(err, &bindErrs) {
+		t.Fatalf("expected BindErrors but got %v", err)
+	}
+
+	got := make([]string, len(bindErrs))
+	for i, e := range bindErrs {
+		got[i] = e.Field + " " + e.Key + " " + e.Value + " " + e.Type.String()
+	}
+	assert.That(t, got, is.DeepEqual([]string{
+		"DB.Port db.port abc int",
+		"Timeout timeout 5 time.Duration",
+		"Backends[1].Port backends.1.port 300 uint8",
+		"Ports[1] ports.1 http int",
+	}))
+
+	assert.That(t, errors.Is(bindErrs[2], strconv.ErrRange), is.Equal(true))
+	// Values read without a File loader report their line and column.
+	assert.That(t, bindErrs[0].Error(), is.Equal(`line 2, column 40: db.port: invalid int "abc": invalid syntax (field DB.Port)`))
+
+	// Values that convert are bound nevertheless.
+	assert.That(t, config.DB.Host, is.Equal("localhost"))
+	assert.That(t, config.Debug, is.Equal(true))
+	assert.That(t, config.Backends[0].Port, is.Equal(uint8(80)))
+}
+
+func TestAppConfig_Bind_richTypes(t *testing.T) {
+	type Config struct {
+		Started  time.Time
+		Endpoint *url.URL
+		IP       net.IP
+		Network  *net.IPNet
+		Buffer   ByteSize `appconf:",max=1GiB"`
+		Pattern  *regexp.Regexp
+		Missing  time.Time
+		Limit    ByteSize  `appconf:",default=10MB"`
+		Since    time.Time `appconf:",default=2020-01-01"`
+	}
+
+	c, err := New(Static(map[string]interface{}{
+		"started":  "2023-04-05T10:11:12Z",
+		"endpoint": "https://example.com/api",
+		"ip":       "::1",
+		"network":  "10.0.0.0/8",
+		"buffer":   "512MiB",
+		"pattern":  "^a+$",
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var config Config
+	if err := c.Bind(&config, Strict()); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.That(t, config.Started, is.Equal(time.Date(2023, 4, 5, 10, 11, 12, 0, time.UTC)))
+	assert.That(t, config.Endpoint.String(), is.Equal("https://example.com/api"))
+	assert.That(t, config.IP.Equal(net.IPv6loopback), is.Equal(true))
+	assert.That(t, config.Network.String(), is.Equal("10.0.0.0/8"))
+	assert.That(t, config.Buffer, is.Equal(512*MiB))
+	assert.That(t, config.Pattern.String(), is.Equal("^a+$"))
+	assert.That(t, config.Missing.IsZero(), is.Equal(true))
+	assert.That(t, config.Limit, is.Equal(10*MB))
+	assert.That(t, config.Since, is.Equal(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)))
+
+	if err := c.Set("buffer", "2GiB"); err != nil {
+		t.Fatal(err)
+	}
+	err = c.Bind(&config)
+	assert.That(t, errors.Is(err, ErrValidation), is.Equal(true))
+
+	if err := c.Set("ip", "localhost"); err != nil {
+		t.Fatal(err)
+	}
+	err = c.Bind(&config)
+	assert.That(t, errors.Is(err, ErrInvalidValue), is.Equal(true))
+}
+
+func TestAppConfig_Bind_pointers(t *testing.T) {
+	type (
+		TLS struct {
+			Cert string
+		}
+
+		Config struct {
+			Port     *int
+			Missing  *int
+			Null     *string
+			TLS      *TLS
+			NoTLS    *TLS
+			Backends []*TLS
+		}
+	)
+
+	c, err := New(Static(map[string]interface{}{
+		"port":     8080,
+		"null":     nil,
+		"tls":      map[string]interface{}{"cert": "cert.pem"},
+		"backends": []interface{}{map[string]interface{}{"cert": "a.pem"}},
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var config Config
+	if err := c.Bind(&config, Strict()); err != nil {
+		t.Fatal(err)
+	}
+
+	port := 8080
+	assert.That(t, config, is.DeepEqual(Config{
+		Port:     &port,
+		TLS:      &TLS{Cert: "cert.pem"},
+		Backends: []*TLS{{Cert: "a.pem"}},
+	}))
+}
+
+func TestAppConfig_Bind_mapsArraysAndInterfaces(t *testing.T) {
+	type (
+		Backend struct {
+			Host string
+			Port int
+		}
+
+		Config struct {
+			Limits   map[string]int
+			Backends map[string]Backend
+			Hosts    [3]string
+			Extra    interface{}
+		}
+	)
+
+	c, err := New(Static(map[string]interface{}{
+		"limits": map[string]interface{}{"api": 100, "web": "200"},
+		"backends": map[string]interface{}{
+			"alpha": map[string]interface{}{"host": "a.local", "port": 8080},
+		},
+		"hosts": []interface{}{"a", "b"},
+		"extra": map[string]interface{}{
+			"tags": []interface{}{"x", 1},
+		},
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var config Config
+	if err := c.Bind(&config, Strict()); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.That(t, config, is.DeepEqual(Config{
+		Limits:   map[string]int{"api": 100, "web": 200},
+		Backends: map[string]Backend{"alpha": {Host: "a.local", Port: 8080}},
+		Hosts:    [3]string{"a", "b", ""},
+		Extra: map[string]interface{}{
+			"tags": []interface{}{"x", int64(1)},
+		},
+	}))
+
+	if err := c.Set("hosts", []string{"a", "b", "c", "d"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Set("limits.api", "many"); err != nil {
+		t.Fatal(err)
+	}
+
+	err = c.Bind(&config)
+
+	var bindErrs BindErrors
+	if !errors.As(err, &bindErrs) {
+		t.Fatalf("expected BindErrors but got %v", err)
+	}
+	got := make([]string, len(bindErrs))
+	for i, e := range bindErrs {
+		got[i] = e.Field + " " + e.Key
+	}
+	assert.That(t, got, is.DeepEqual([]string{`Limits["api"] limits.api`, "Hosts hosts"}))
+}
+
+func TestAppConfig_Bind_squash(t *testing.T) {
+	type (
+		Common struct {
+			Name string
+		}
+
+		common struct {
+			Version int
+		}
+
+		Server struct {
+			Port int
+		}
+
+		Config struct {
+			Common  `appconf:",squash"`
+			common  `appconf:",squash"`
+			*Server `appconf:",squash"`
+			Host    string
+		}
+	)
+
+	c, err := New(Static(map[string]interface{}{
+		"name":    "app",
+		"version": 2,
+		"port":    8080,
+		"host":    "localhost",
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var config Config
+	if err := c.Bind(&config, Strict()); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.That(t, config.Name, is.Equal("app"))
+	assert.That(t, config.Version, is.Equal(2))
+	assert.That(t, config.Port, is.Equal(8080))
+	assert.That(t, config.Host, is.Equal("localhost"))
+
+	var invalid struct {
+		Port int `appconf:",squash"`
+	}
+	assert.That(t, errors.Is(c.Bind(&invalid), ErrInvalidTag), is.Equal(true))
+}
+
+func TestAppConfig_Bind_nonStruct(t *testing.T) {
+	c, err := New(JSONFile("./testdata/config.json", true))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var port int
+	if err := c.Sub("db.port").Bind(&port); err != nil {
+		t.Fatal(err)
+	}
+	assert.That(t, port, is.Equal(3306))
+
+	var tags []string
+	if err := c.Sub("backends.0.tags").Bind(&tags); err != nil {
+		t.Fatal(err)
+	}
+	assert.That(t, tags, is.DeepEqual([]string{"a", "1"}))
+
+	var db map[string]string
+	if err := c.Sub("db").Bind(&db); err != nil {
+		t.Fatal(err)
+	}
+	assert.That(t, db["port"], is.Equal("3306"))
+
+	m := map[string]interface{}{"existing": true}
+	if err := c.Sub("web").Bind(&m); err != nil {
+		t.Fatal(err)
+	}
+	assert.That(t, m, is.DeepEqual(map[string]interface{}{
+		"existing":  true,
+		"address":   "localhost:8080",
+		"timeout":   "2s",
+		"authorize": true,
+	}))
+
+	ports := map[int]int{1: 1}
+	err = c.Sub("db").Bind(&ports)
+	assert.That(t, errors.Is(err, ErrInvalidBindingType), is.Equal(true))
+}
+
+func TestAppConfig_Bind_preservesExistingValues(t *testing.T) {
+	type (
+		DB struct {
+			Host    string
+			Port    int
+			Options map[string]string
+		}
+
+		TLS struct {
+			Cert string
+			Key  string
+		}
+
+		Config struct {
+			DB    DB
+			TLS   *TLS
+			Ports [3]int
+			Pools map[string]DB
+		}
+	)
+
+	c, err := New(Static(map[string]interface{}{
+		"db": map[string]interface{}{
+			"host":    "db.local",
+			"options": map[string]interface{}{"sslmode": "require"},
+		},
+		"tls":   map[string]interface{}{"cert": "cert.pem"},
+		"ports": []interface{}{8080},
+		"pools": map[string]interface{}{
+			"primary": map[string]interface{}{"port": 6432},
+		},
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tls := &TLS{Key: "key.pem"}
+	config := Config{
+		DB: DB{
+			Host:    "localhost",
+			Port:    5432,
+			Options: map[string]string{"timeout": "5s"},
+		},
+		TLS:   tls,
+		Ports: [3]int{80, 443, 8443},
+		Pools: map[string]DB{
+			"primary": {Host: "pool.local", Port: 5432},
+		},
+	}
+	if err := c.Bind(&config); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.That(t, config, is.DeepEqual(Config{
+		DB: DB{
+			Host:    "db.local",
+			Port:    5432,
+			Options: map[string]string{"timeout": "5s", "sslmode": "require"},
+		},
+		TLS:   &TLS{Cert: "cert.pem", Key: "key.pem"},
+		Ports: [3]int{8080, 443, 8443},
+		Pools: map[string]DB{
+			"primary": {Host: "pool.local", Port: 6432},
+		},
+	}))
+
+	// The previously referenced value is not modified.
+	assert.That(t, *tls, is.Equal(TLS{Key: "key.pem"}))
+}
+
+func TestAppConfig_Bind_slicePolicy(t *testing.T) {
+	type (
+		Backend struct {
+			Host string
+			Port int
+		}
+
+		Config struct {
+			Tags     []string
+			Backends []Backend
+		}
+	)
+
+	c, err := New(Static(map[string]interface{}{
+		"tags": []interface{}{"c"},
+		"backends": []interface{}{
+			map[string]interface{}{"host": "alpha.local"},
+		},
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	prefilled := func() Config {
+		return Config{
+			Tags: []string{"a", "b"},
+			Backends: []Backend{
+				{Host: "localhost", Port: 8080},
+				{Host: "localhost", Port: 8081},
+			},
+		}
+	}
+
+	tests := map[SlicePolicy]Config{
+		SliceReplace: {
+			Tags:     []string{"c"},
+			Backends: []Backend{{Host: "alpha.local"}},
+		},
+		SliceAppend: {
+			Tags: []string{"a", "b", "c"},
+			Backends: []Backend{
+				{Host: "localhost", Port: 8080},
+				{Host: "localhost", Port: 8081},
+				{Host: "alpha.local"},
+			},
+		},
+		SliceMergeByIndex: {
+			Tags: []string{"c", "b"},
+			Backends: []Backend{
+				{Host: "alpha.local", Port: 8080},
+				{Host: "localhost", Port: 8081},
+			},
+		},
+	}
+
+	for policy, want := range tests {
+		config := prefilled()
+		if err := c.Bind(&config, WithSlicePolicy(policy)); err != nil {
+			t.Fatal(err)
+		}
+		assert.That(t, config, is.DeepEqual(want))
+	}
+}
+
+func TestAppConfig_Bind_slicePolicyWithoutCurrentValue(t *testing.T) {
+	type Config struct {
+		Groups  map[string][]string
+		Matrix  [][]string
+		Aliases *[]string
+	}
+
+	c, err := New(Static(map[string]interface{}{
+		"groups":  map[string]interface{}{"admins": []interface{}{"alice"}},
+		"matrix":  []interface{}{[]interface{}{"a", "b"}},
+		"aliases": []interface{}{"x"},
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config := Config{
+		Groups: map[string][]string{"users": {"bob"}},
+	}
+	if err := c.Bind(&config, WithSlicePolicy(SliceAppend)); err != nil {
+		t.Fatal(err)
+	}
+
+	aliases := []string{"x"}
+	assert.That(t, config, is.DeepEqual(Config{
+		Groups:  map[string][]string{"users": {"bob"}, "admins": {"alice"}},
+		Matrix:  [][]string{{"a", "b"}},
+		Aliases: &aliases,
+	}))
+}
+
+func TestAppConfig_Bind_mapTypedValues(t *testing.T) {
+	c, err := New(JSONFile("./testdata/config.json", true))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var config map[string]interface{}
+	if err := c.Bind(&config); err != nil {
+		t.Fatal(err)
+	}
+
+	db := config["db"].(map[string]interface{})
+	assert.That(t, db["port"], is.DeepEqual[interface{}](int64(3306)))
+	assert.That(t, db["host"], is.DeepEqual[interface{}]("localhost"))
+}
+
+func TestAppConfig_Bind_null(t *testing.T) {
+	type Config struct {
+		Host string
+		Port int
+		Tags []string
+	}
+
+	c, err := New(JSONFile("./testdata/config.json", true), LoaderFunc(func() (*Node, error) {
+		return JSON(strings.NewReader(`{"db": {"host": null, "port": null, "tags": null}}`))
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config := Config{
+		Host: "example.com",
+		Port: 1234,
+		Tags: []string{"a"},
+	}
+	if err := c.Sub("db").Bind(&config); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.That(t, config, is.DeepEqual(Config{
+		Host: "example.com",
+		Port: 1234,
+		Tags: []string{"a"},
+	}))
+
+	var m map[string]interface{}
+	if err := c.Sub("db").Bind(&m); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.That(t, m["host"], is.DeepEqual[interface{}](nil))
+}
+
+func TestAppConfig_Bind_defaults(t *testing.T) {
+	type (
+		DB struct {
+			Host string `appconf:",default=localhost"`
+			Port int    `appconf:",default=5432,min=1"`
+		}
+
+		Web struct {
+			Address string        `appconf:",default=:8080"`
+			Timeout time.Duration `appconf:",default=5s"`
+			Origins []string      `appconf:",default=a.example.com, b.example.com"`
+			Ports   []int         `appconf:",default=80,443,required"`
+		}
+
+		Config struct {
+			DB  DB
+			Web Web
+		}
+	)
+
+	c, err := New(Static(map[string]interface{}{
+		"web.address": "localhost:8080",
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var config Config
+	if err := c.Bind(&config); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.That(t, config, is.DeepEqual(Config{
+		DB: DB{
+			Host: "localhost",
+			Port: 5432,
+		},
+		Web: Web{
+			Address: "localhost:8080",
+			Timeout: 5 * time.Second,
+			Origins: []string{"a.example.com", "b.example.com"},
+			Ports:   []int{80, 443},
+		},
+	}))
+}
+
+func TestAppConfig_Bind_invalidDefault(t *testing.T) {
+	tests := map[string]interface{}{
+		"int": &struct {
+			Port int `appconf:",default=http"`
+		}{},
+		"overflow": &struct {
+			Port int8 `appconf:",default=300"`
+		}{},
+		"duration": &struct {
+			Timeout time.Duration `appconf:",default=5"`
+		}{},
+		"slice": &struct {
+			Ports []uint `appconf:",default=80,-1"`
+		}{},
+	}
+
+	c, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, v := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := c.Bind(v)
+			assert.That(t, errors.Is(err, ErrInvalidTag), is.Equal(true))
+		})
+	}
+}
+
+func TestAppConfig_Bind_strict(t *testing.T) {
+	type Config struct {
+		DB struct {
+			Host string
+			Port int
+		}
+		Backends []struct {
+			Host string
+			Tags []string
+		}
+	}
+
+	c, err := New(
+		JSONFile("./testdata/config.json", true),
+		Static(map[string]interface{}{
+			"databse.host": "localhost",
+			"web":          nil,
+		}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var config Config
+	err = c.Bind(&config, Strict())
+	assert.That(t, errors.Is(err, ErrUnusedKeys), is.Equal(true))
+	assert.That(t, err.Error(), is.Equal("unused keys: backends.0.port, backends.1.port, databse.host, db.password, db.type, db.user, web"))
+
+	var unused []string
+	if err := c.Sub("db").Bind(&config.DB, ReportUnused(&unused)); err != nil {
+		t.Fatal(err)
+	}
+	assert.That(t, unused, is.DeepEqual([]string{"password", "type", "user"}))
+}
+
+func TestAppConfig_Bind_strictAllUsed(t *testing.T) {
+	type Config struct {
+		Web struct {
+			Address string
+			Timeout time.Duration
+			Options []string
+		}
+		Debug bool
+	}
+
+	c, err := New(Static(map[string]interface{}{
+		"web.address": "localhost",
+		"web.timeout": "2s",
+		"web.options": []string{"a", "b"},
+		"debug":       nil,
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var config Config
+	assert.That(t, c.Bind(&config, Strict()), is.DeepEqual[error](nil))
+}
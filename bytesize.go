@@ -0,0 +1,115 @@
+package appconf
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ByteSize is an amount of bytes such as a buffer or file size. Config values are given as a number with an
+// optional unit, i.e. 512MiB or 10MB.
+type ByteSize uint64
+
+// Byte size units. Units with an i use powers of 1024, all others use powers of 1000.
+const (
+	Byte ByteSize = 1
+
+	KB ByteSize = 1000 * Byte
+	MB ByteSize = 1000 * KB
+	GB ByteSize = 1000 * MB
+	TB ByteSize = 1000 * GB
+	PB ByteSize = 1000 * TB
+
+	KiB ByteSize = 1024 * Byte
+	MiB ByteSize = 1024 * KiB
+	GiB ByteSize = 1024 * MiB
+	TiB ByteSize = 1024 * GiB
+	PiB ByteSize = 1024 * TiB
+)
+
+// byteSizeUnits maps the lower case unit names to their sizes.
+var byteSizeUnits = map[string]ByteSize{
+	"":    Byte,
+	"b":   Byte,
+	"k":   KiB,
+	"kb":  KB,
+	"kib": KiB,
+	"m":   MiB,
+	"mb":  MB,
+	"mib": MiB,
+	"g":   GiB,
+	"gb":  GB,
+	"gib": GiB,
+	"t":   TiB,
+	"tb":  TB,
+	"tib": TiB,
+	"p":   PiB,
+	"pb":  PB,
+	"pib": PiB,
+}
+
+// ParseByteSize parses s as a number followed by an optional unit, i.e. 512MiB, 1.5 GB or 1024. Units are
+// case insensitive. The units KB, MB, GB, TB and PB use powers of 1000, the units KiB, MiB, GiB, TiB and PiB as
+// well as the short forms K, M, G, T and P use powers of 1024.
+func ParseByteSize(s string) (ByteSize, error) {
+	s = strings.TrimSpace(s)
+
+	i := strings.IndexFunc(s, func(r rune) bool {
+		return (r < '0' || r > '9') && r != '.'
+	})
+	if i < 0 {
+		i = len(s)
+	}
+
+	num, unit := s[:i], strings.ToLower(strings.TrimSpace(s[i:]))
+
+	factor, ok := byteSizeUnits[unit]
+	if !ok || num == "" {
+		return 0, fmt.Errorf("invalid byte size: %q", s)
+	}
+
+	if u, err := strconv.ParseUint(num, 10, 64); err == nil {
+		if u > math.MaxUint64/uint64(factor) {
+			return 0, fmt.Errorf("byte size out of range: %q", s)
+		}
+		return ByteSize(u) * factor, nil
+	}
+
+	f, err := strconv.ParseFloat(num, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid byte size: %q", s)
+	}
+	f *= float64(factor)
+	if f >= math.MaxUint64 {
+		return 0, fmt.Errorf("byte size out of range: %q", s)
+	}
+	return ByteSize(f), nil
+}
+
+// String formats s using the largest unit that represents s without a fraction, i.e. 512MiB or 10MB.
+func (s ByteSize) String() string {
+	units := []struct {
+		size ByteSize
+		name string
+	}{
+		{PiB, "PiB"},
+		{PB, "PB"},
+		{TiB, "TiB"},
+		{TB, "TB"},
+		{GiB, "GiB"},
+		{GB, "GB"},
+		{MiB, "MiB"},
+		{MB, "MB"},
+		{KiB, "KiB"},
+		{KB, "KB"},
+	}
+
+	for _, u := range units {
+		if s >= u.size && s%u.size == 0 {
+			return strconv.FormatUint(uint64(s/u.size), 10) + u.name
+		}
+	}
+
+	return strconv.FormatUint(uint64(s), 10) + "B"
+}
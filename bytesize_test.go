@@ -0,0 +1,44 @@
+package appconf
+
+import (
+	"testing"
+
+	"github.com/halimath/assertthat-go/assert"
+	"github.com/halimath/assertthat-go/is"
+)
+
+func TestParseByteSize(t *testing.T) {
+	tests := map[string]ByteSize{
+		"1024":    1024,
+		"512MiB":  512 * MiB,
+		"10MB":    10 * MB,
+		"10 mb":   10 * MB,
+		"1.5GiB":  1536 * MiB,
+		"2k":      2 * KiB,
+		"0":       0,
+		"16 PiB ": 16 * PiB,
+	}
+
+	for in, want := range tests {
+		got, err := ParseByteSize(in)
+		if err != nil {
+			t.Errorf("%q: %s", in, err)
+			continue
+		}
+		assert.That(t, got, is.Equal(want))
+	}
+
+	for _, in := range []string{"", "MiB", "10XB", "1.2.3MB", "-1", "100000PiB"} {
+		if _, err := ParseByteSize(in); err == nil {
+			t.Errorf("%q: expected error", in)
+		}
+	}
+}
+
+func TestByteSize_String(t *testing.T) {
+	assert.That(t, (512 * MiB).String(), is.Equal("512MiB"))
+	assert.That(t, (10 * MB).String(), is.Equal("10MB"))
+	assert.That(t, (3 * KiB).String(), is.Equal("3KiB"))
+	assert.That(t, ByteSize(1023).String(), is.Equal("1023B"))
+	assert.That(t, ByteSize(0).String(), is.Equal("0B"))
+}
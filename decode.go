@@ -0,0 +1,127 @@
+package appconf
+
+import (
+	"encoding"
+	"net"
+	"net/url"
+	"reflect"
+	"regexp"
+	"sync"
+	"time"
+)
+
+// Unmarshaler is implemented by types that convert config values to themselves. UnmarshalAppConf receives
+// the Node stored under the bound key, which may contain nested values. It is called on a pointer to a newly
+// allocated value.
+type Unmarshaler interface {
+	UnmarshalAppConf(n *Node) error
+}
+
+// decodeFunc converts a Node to a value of a type bound from a single config value.
+type decodeFunc func(n *Node) (reflect.Value, error)
+
+var (
+	decodersLock sync.RWMutex
+
+	// decoders maps types that are bound from a single config value to the functions converting a Node to a
+	// value of that type. It is consulted before the type's kind, so that i.e. a time.Time is not bound as a
+	// nested struct.
+	decoders = map[reflect.Type]decodeFunc{
+		reflect.TypeOf(time.Duration(0)): valueDecoder((*Node).GetDurationE),
+		reflect.TypeOf(time.Time{}):      valueDecoder((*Node).GetTimeE),
+		reflect.TypeOf(&url.URL{}):       valueDecoder((*Node).GetURLE),
+		reflect.TypeOf(net.IP{}):         valueDecoder((*Node).GetIPE),
+		reflect.TypeOf(&net.IPNet{}):     valueDecoder((*Node).GetIPNetE),
+		reflect.TypeOf(ByteSize(0)):      valueDecoder((*Node).GetByteSizeE),
+		reflect.TypeOf(&regexp.Regexp{}): valueDecoder((*Node).GetRegexpE),
+	}
+
+	unmarshalerType     = reflect.TypeOf((*Unmarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// RegisterDecoder registers decode to convert config values to values of type T. The decoder is used by Bind,
+// Get and typed keys for all values of type T and takes precedence over the built-in conversions as well as
+// Unmarshaler and encoding.TextUnmarshaler implementations. Registering a decoder for a type replaces a
+// previously registered one.
+func RegisterDecoder[T any](decode func(n *Node) (T, error)) {
+	var v T
+	t := reflect.TypeOf(&v).Elem()
+
+	decodersLock.Lock()
+	defer decodersLock.Unlock()
+
+	decoders[t] = valueDecoder(decode)
+}
+
+// valueDecoder adapts the function f to be used as a decodeFunc.
+func valueDecoder[T any](f func(*Node) (T, error)) decodeFunc {
+	return func(n *Node) (reflect.Value, error) {
+		v, err := f(n)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		return reflect.ValueOf(&v).Elem(), nil
+	}
+}
+
+// decoderFor returns the function to decode values of type t. It reports false if values of t are not
+// decoded by a registered decoder, an Unmarshaler or an encoding.TextUnmarshaler.
+func decoderFor(t reflect.Type) (decodeFunc, bool) {
+	decodersLock.RLock()
+	decode, ok := decoders[t]
+	decodersLock.RUnlock()
+
+	if ok {
+		return decode, true
+	}
+
+	switch {
+	case reflect.PointerTo(t).Implements(unmarshalerType):
+		return newValueDecoder(t, unmarshalAppConf), true
+	case t.Kind() == reflect.Pointer && t.Implements(unmarshalerType):
+		return newPointerDecoder(t, unmarshalAppConf), true
+	case reflect.PointerTo(t).Implements(textUnmarshalerType):
+		return newValueDecoder(t, unmarshalText), true
+	case t.Kind() == reflect.Pointer && t.Implements(textUnmarshalerType):
+		return newPointerDecoder(t, unmarshalText), true
+	}
+
+	return nil, false
+}
+
+// newValueDecoder creates a decodeFunc for t, which decodes a config value by calling unmarshal with a
+// pointer to a new value of type t.
+func newValueDecoder(t reflect.Type, unmarshal func(ptr interface{}, n *Node) error) decodeFunc {
+	return func(n *Node) (reflect.Value, error) {
+		ptr := reflect.New(t)
+		if err := unmarshal(ptr.Interface(), n); err != nil {
+			return reflect.Value{}, err
+		}
+		return ptr.Elem(), nil
+	}
+}
+
+// newPointerDecoder creates a decodeFunc for the pointer type t, which decodes a config value by calling
+// unmarshal with a pointer to a new value of t's element type.
+func newPointerDecoder(t reflect.Type, unmarshal func(ptr interface{}, n *Node) error) decodeFunc {
+	return func(n *Node) (reflect.Value, error) {
+		ptr := reflect.New(t.Elem())
+		if err := unmarshal(ptr.Interface(), n); err != nil {
+			return reflect.Value{}, err
+		}
+		return ptr, nil
+	}
+}
+
+func unmarshalAppConf(ptr interface{}, n *Node) error {
+	return ptr.(Unmarshaler).UnmarshalAppConf(n)
+}
+
+func unmarshalText(ptr interface{}, n *Node) error {
+	s, err := n.GetStringE()
+	if err != nil {
+		return err
+	}
+	return ptr.(encoding.TextUnmarshaler).UnmarshalText([]byte(s))
+}
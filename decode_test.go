@@ -0,0 +1,140 @@
+package appconf
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/halimath/assertthat-go/assert"
+	"github.com/halimath/assertthat-go/is"
+)
+
+type testLevel int
+
+func (l *testLevel) UnmarshalText(text []byte) error {
+	switch strings.ToLower(string(text)) {
+	case "debug":
+		*l = 0
+	case "info":
+		*l = 1
+	case "error":
+		*l = 2
+	default:
+		return fmt.Errorf("invalid level: %q", text)
+	}
+	return nil
+}
+
+type testEndpoint struct {
+	Host string
+	Port int
+}
+
+// UnmarshalAppConf accepts an endpoint either as host:port or as a nested structure.
+func (e *testEndpoint) UnmarshalAppConf(n *Node) error {
+	if len(n.Children) > 0 {
+		// Bind to a type without the UnmarshalAppConf method to prevent an endless recursion.
+		type plain testEndpoint
+		return bind(n, (*plain)(e))
+	}
+
+	host, port, err := net.SplitHostPort(n.Value)
+	if err != nil {
+		return err
+	}
+	e.Host = host
+	e.Port, err = strconv.Atoi(port)
+	return err
+}
+
+type testCents int64
+
+func TestRegisterDecoder(t *testing.T) {
+	RegisterDecoder(func(n *Node) (testCents, error) {
+		f, err := n.GetFloat64E()
+		return testCents(f * 100), err
+	})
+
+	type Config struct {
+		Price testCents
+	}
+
+	c, err := New(Static(map[string]interface{}{
+		"price": "12.5",
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var config Config
+	if err := c.Bind(&config); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.That(t, config.Price, is.Equal(testCents(1250)))
+	assert.That(t, MustGet[testCents](c, "price"), is.Equal(testCents(1250)))
+}
+
+func TestBind_unmarshalers(t *testing.T) {
+	type Config struct {
+		Level    testLevel
+		LevelPtr *testLevel
+		Primary  testEndpoint
+		Backup   *testEndpoint
+	}
+
+	c, err := New(Static(map[string]interface{}{
+		"level":    "error",
+		"levelptr": "info",
+		"primary":  "localhost:8080",
+		"backup": map[string]interface{}{
+			"host": "backup.local",
+			"port": 9090,
+		},
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var config Config
+	if err := c.Bind(&config, Strict()); err != nil {
+		t.Fatal(err)
+	}
+
+	info := testLevel(1)
+	assert.That(t, config, is.DeepEqual(Config{
+		Level:    2,
+		LevelPtr: &info,
+		Primary:  testEndpoint{Host: "localhost", Port: 8080},
+		Backup:   &testEndpoint{Host: "backup.local", Port: 9090},
+	}))
+}
+
+func TestBind_unmarshalerError(t *testing.T) {
+	type Config struct {
+		Level   testLevel
+		Primary testEndpoint
+	}
+
+	c, err := New(Static(map[string]interface{}{
+		"level":   "verbose",
+		"primary": "localhost",
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var config Config
+	err = c.Bind(&config)
+
+	var bindErrs BindErrors
+	if !errors.As(err, &bindErrs) {
+		t.Fatalf("expected BindErrors but got %v", err)
+	}
+	assert.That(t, len(bindErrs), is.Equal(2))
+	assert.That(t, bindErrs[0].Field, is.Equal("Level"))
+	assert.That(t, bindErrs[1].Field, is.Equal("Primary"))
+}
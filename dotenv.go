@@ -0,0 +1,203 @@
+package appconf
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// Dotenv parses the content of r in dotenv format and converts it to a Node tree. The variable names are
+// mapped to keys the same way Env does, i.e. DB_HOST becomes db.host.
+//
+// Each line contains a single assignment NAME=value, optionally prefixed with export. Empty lines and lines
+// starting with # are ignored. Values may be
+//
+//   - unquoted: surrounding whitespace and a trailing comment starting with # are removed
+//   - single quoted: the value is taken literally and is not interpolated
+//   - double quoted: the value may span multiple lines and contain the escape sequences \n, \r, \t, \", \\
+//     and \$
+//
+// References such as ${key} in unquoted and double quoted values are resolved by New. The escape sequence \$
+// prevents this: a double quoted value containing only escaped references such as \${key} is returned as
+// ${key} and is not interpolated. If the value contains unescaped references as well, the escaped ones are
+// returned as $${key}, which interpolation turns into the literal ${key}.
+//
+// Syntax errors are reported as a *ParseError.
+func Dotenv(r io.Reader) (*Node, error) {
+	return parseDotenv(r, "")
+}
+
+// DotenvFile creates a Loader which loads variables from the dotenv file name. Only variables with a name
+// starting with prefix are considered. Use the empty string to select all variables. See Dotenv for a
+// description of the format.
+func DotenvFile(name string, mandatory bool, prefix string) Loader {
+	return File(name, mandatory, func(r io.Reader) (*Node, error) {
+		return parseDotenv(r, prefix)
+	})
+}
+
+// dotenvNamePattern matches valid variable names.
+var dotenvNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.]*$`)
+
+// parseDotenv parses the dotenv content read from r selecting only variables with a name starting with
+// prefix.
+func parseDotenv(r io.Reader, prefix string) (*Node, error) {
+	if len(prefix) > 0 && !strings.HasSuffix(prefix, "_") {
+		prefix += "_"
+	}
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	n := NewNode("")
+	lines := strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n")
+
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimLeft(lines[i], " \t")
+		if line == "" || line[0] == '#' {
+			continue
+		}
+
+		if strings.HasPrefix(line, "export ") || strings.HasPrefix(line, "export\t") {
+			line = strings.TrimLeft(line[len("export"):], " \t")
+		}
+
+		name, rest, ok := strings.Cut(line, "=")
+		name = strings.TrimRight(name, " \t")
+		if !ok || !dotenvNamePattern.MatchString(name) {
+			return nil, dotenvError(lines, i, line, "invalid assignment")
+		}
+
+		rest = strings.TrimLeft(rest, " \t")
+		valueLine, valueColumn := i+1, len(lines[i])-len(rest)+1
+
+		var (
+			value   string
+			literal bool
+		)
+		switch {
+		case strings.HasPrefix(rest, `"`):
+			value, literal, i, rest, err = parseDotenvDoubleQuoted(lines, i, rest)
+			if err != nil {
+				return nil, err
+			}
+		case strings.HasPrefix(rest, "'"):
+			end := strings.IndexByte(rest[1:], '\'')
+			if end < 0 {
+				return nil, dotenvError(lines, i, rest, "unterminated single quoted value")
+			}
+			value, rest, literal = rest[1:end+1], rest[end+2:], true
+		default:
+			value, rest = cutComment(rest, "#"), ""
+		}
+
+		if rest = strings.TrimLeft(rest, " \t"); rest != "" && rest[0] != '#' {
+			return nil, dotenvError(lines, i, rest, "unexpected characters after quoted value")
+		}
+
+		if !strings.HasPrefix(name, prefix) {
+			continue
+		}
+
+		v := NewNode(value)
+		v.source.Line, v.source.Column = valueLine, valueColumn
+		v.literal = literal
+		n.set(ParseKeyPath(envKeyToMapKey(name, prefix)), v)
+	}
+
+	n.annotate(Source{Loader: "dotenv"})
+	return n, nil
+}
+
+// parseDotenvDoubleQuoted parses the double quoted value starting at s, which is the remainder of lines[i].
+// It returns the unquoted value, the index of the line containing the closing quote and the remainder of that
+// line following the closing quote. literal reports whether the value must not be interpolated because it
+// contains escaped references (\${) only.
+//
+// A value containing both escaped and unescaped references is returned with the escaped references in the
+// form $${, which interpolation turns into a literal ${.
+func parseDotenvDoubleQuoted(lines []string, i int, s string) (value string, literal bool, end int, rest string, err error) {
+	start := i
+	var (
+		b                  strings.Builder
+		escaped, unescaped bool
+	)
+
+	s = s[1:]
+	for {
+		for j := 0; j < len(s); j++ {
+			switch c := s[j]; c {
+			case '"':
+				value = b.String()
+				if escaped && !unescaped {
+					return strings.ReplaceAll(value, "$${", "${"), true, i, s[j+1:], nil
+				}
+				return value, false, i, s[j+1:], nil
+			case '\\':
+				if j+1 == len(s) {
+					b.WriteByte(c)
+					continue
+				}
+				j++
+				switch e := s[j]; e {
+				case 'n':
+					b.WriteByte('\n')
+				case 'r':
+					b.WriteByte('\r')
+				case 't':
+					b.WriteByte('\t')
+				case '"', '\\':
+					b.WriteByte(e)
+				case '$':
+					// Escape an escaped reference, so it is not resolved by interpolation.
+					if j+1 < len(s) && s[j+1] == '{' {
+						b.WriteByte('$')
+						escaped = true
+					}
+					b.WriteByte(e)
+				default:
+					b.WriteByte(c)
+					b.WriteByte(e)
+				}
+			default:
+				if c == '$' && j+1 < len(s) && s[j+1] == '{' {
+					unescaped = true
+				}
+				b.WriteByte(c)
+			}
+		}
+
+		i++
+		if i == len(lines) {
+			return "", false, i, "", &ParseError{
+				Source: Source{Line: start + 1},
+				Err:    fmt.Errorf("%w: unterminated double quoted value", ErrUnsupportedValue),
+			}
+		}
+		b.WriteByte('\n')
+		s = lines[i]
+	}
+}
+
+// cutComment returns the unquoted value s without a trailing comment and surrounding whitespace. A comment
+// starts with one of the characters in commentChars preceded by whitespace.
+func cutComment(s, commentChars string) string {
+	for i := 1; i < len(s); i++ {
+		if strings.IndexByte(commentChars, s[i]) >= 0 && (s[i-1] == ' ' || s[i-1] == '\t') {
+			s = s[:i]
+			break
+		}
+	}
+	return strings.TrimSpace(s)
+}
+
+// dotenvError creates a *ParseError describing a syntax error at s, which is the remainder of lines[i].
+func dotenvError(lines []string, i int, s, msg string) error {
+	return &ParseError{
+		Source: Source{Line: i + 1, Column: len(lines[i]) - len(s) + 1},
+		Err:    fmt.Errorf("%w: %s", ErrUnsupportedValue, msg),
+	}
+}
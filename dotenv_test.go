@@ -0,0 +1,137 @@
+package appconf
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/halimath/assertthat-go/assert"
+	"github.com/halimath/assertthat-go/is"
+)
+
+func TestDotenv(t *testing.T) {
+	got, err := Dotenv(strings.NewReader(`# database settings
+DB_HOST=localhost
+export DB_PORT = 5432   # default port
+DB_USER='admin # not a comment'
+DB_PASSWORD="s3\"cr\\3t\$"
+
+WEB_MOTD="Hello
+  world\tagain"
+WEB_EMPTY=
+WEB_URL=http://example.com/#anchor
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := &AppConfig{n: got}
+	assert.That(t, c.GetString("db.host"), is.Equal("localhost"))
+	assert.That(t, c.GetInt("db.port"), is.Equal(5432))
+	assert.That(t, c.GetString("db.user"), is.Equal("admin # not a comment"))
+	assert.That(t, c.GetString("db.password"), is.Equal(`s3"cr\3t$`))
+	assert.That(t, c.GetString("web.motd"), is.Equal("Hello\n  world\tagain"))
+	assert.That(t, c.HasKey("web.empty"), is.Equal(true))
+	assert.That(t, c.GetString("web.url"), is.Equal("http://example.com/#anchor"))
+
+	assert.That(t, got.resolve(ParseKeyPath("db.port")).Source(), is.Equal(Source{Loader: "dotenv", Line: 3, Column: 18}))
+	assert.That(t, got.resolve(ParseKeyPath("web.empty")).Source(), is.Equal(Source{Loader: "dotenv", Line: 9, Column: 11}))
+}
+
+func TestDotenvFile_interpolation(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), ".env")
+	writeFile(t, filename, `B=x
+A='lit ${B}'
+C="esc \${B} and ${B}"
+D=ref ${B}
+E='open ${B'
+F="only \${B}"
+`)
+
+	c, err := New(DotenvFile(filename, true, ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.That(t, c.GetString("a"), is.Equal("lit ${B}"))
+	assert.That(t, c.GetString("c"), is.Equal("esc ${B} and x"))
+	assert.That(t, c.GetString("d"), is.Equal("ref x"))
+	assert.That(t, c.GetString("e"), is.Equal("open ${B"))
+	assert.That(t, c.GetString("f"), is.Equal("only ${B}"))
+}
+
+func TestDotenv_escapedReferences(t *testing.T) {
+	content := `A="\${B}"
+C="esc \${B} and ${B}"
+`
+
+	got, err := Dotenv(strings.NewReader(content))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.That(t, got.resolve(ParseKeyPath("a")).Value, is.Equal("${B}"))
+	assert.That(t, got.resolve(ParseKeyPath("c")).Value, is.Equal("esc $${B} and ${B}"))
+
+	filename := filepath.Join(t.TempDir(), ".env")
+	writeFile(t, filename, content)
+
+	c, err := NewWithOptions([]Loader{DotenvFile(filename, true, "")}, WithoutInterpolation())
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.That(t, c.GetString("a"), is.Equal("${B}"))
+	assert.That(t, c.GetString("c"), is.Equal("esc $${B} and ${B}"))
+}
+
+func TestDotenv_errors(t *testing.T) {
+	tests := map[string]string{
+		"A=1\nno assignment\n":    "line 2, column 1: unsupported value: invalid assignment",
+		"A=\"open\nB=2\n":         "line 1: unsupported value: unterminated double quoted value",
+		"A='open\n":               "line 1, column 3: unsupported value: unterminated single quoted value",
+		"A=\"closed\" trailing\n": "line 1, column 12: unsupported value: unexpected characters after quoted value",
+	}
+
+	for content, want := range tests {
+		_, err := Dotenv(strings.NewReader(content))
+
+		var parseErr *ParseError
+		assert.That(t, errors.As(err, &parseErr), is.Equal(true))
+		assert.That(t, err.Error(), is.Equal(want))
+	}
+}
+
+func TestDotenvFile(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), ".env")
+	writeFile(t, filename, "APP_DB_HOST=localhost\nOTHER=value\n")
+
+	c, err := New(DotenvFile(filename, true, "APP"), DotenvFile(filepath.Join(t.TempDir(), "missing.env"), false, ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.That(t, c.GetString("db.host"), is.Equal("localhost"))
+	assert.That(t, c.HasKey("other"), is.Equal(false))
+	assert.That(t, c.Explain("db.host").String(), is.Equal("db.host\n* dotenv ("+filename+":1:13): \"localhost\""))
+}
+
+func TestDotenv_roundTrip(t *testing.T) {
+	c, err := New(JSONFile("./testdata/config.json", true), Static(map[string]interface{}{
+		"motd": "Hello \"world\"\n\tbye $HOME",
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var b strings.Builder
+	if err := c.WriteEnv(&b, "APP"); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := Dotenv(strings.NewReader(b.String()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.That(t, (&AppConfig{n: got}).GetString("app.motd"), is.Equal("Hello \"world\"\n\tbye $HOME"))
+	assert.That(t, (&AppConfig{n: got}).GetInt("app.backends.1.port"), is.Equal(8081))
+}
@@ -0,0 +1,119 @@
+package appconf
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Definition describes the definition of a key by a single loader.
+type Definition struct {
+	// Source describes where the definition has been loaded from.
+	Source Source
+	// Value is the defined value. It is empty for keys with nested values.
+	Value string
+	// Kind is the kind of the defined value. It is KindUnset if the definition removes the key.
+	Kind Kind
+	// Effective is true for the definition that is in effect.
+	Effective bool
+}
+
+func (d Definition) String() string {
+	var b strings.Builder
+	if d.Effective {
+		b.WriteString("* ")
+	} else {
+		b.WriteString("  ")
+	}
+	b.WriteString(d.Source.String())
+	switch {
+	case d.Kind == KindNull || d.Kind == KindUnset:
+		fmt.Fprintf(&b, ": %s", d.Kind)
+	case d.Value != "":
+		fmt.Fprintf(&b, ": %q", d.Value)
+	}
+	return b.String()
+}
+
+// Explanation lists all definitions of a single key.
+type Explanation struct {
+	// Key is the explained key.
+	Key string
+	// Definitions lists the definitions of Key in order of precedence, starting with the most significant
+	// one. It is empty if Key is not defined.
+	Definitions []Definition
+}
+
+func (e Explanation) String() string {
+	var b strings.Builder
+	b.WriteString(e.Key)
+	if len(e.Definitions) == 0 {
+		b.WriteString(": not defined")
+	}
+	for _, d := range e.Definitions {
+		b.WriteString("\n")
+		b.WriteString(d.String())
+	}
+	return b.String()
+}
+
+// Explain explains where the value stored under key came from. It lists every loader (and any value set
+// with Set) that defined key in order of precedence and marks the definition that is in effect. A loader
+// removing key (or one of its parents) is listed with a definition of kind KindUnset; a loader setting one
+// of key's parents to null is listed with a definition of kind KindNull. No definition is in effect if the
+// most significant definition is of one of these. Secret values are reported as *** (see
+// WithSecretKeys).
+func (c *AppConfig) Explain(key string) Explanation {
+	s := c.Snapshot()
+	path := ParseKeyPath(key)
+
+	e := Explanation{
+		Key: path.Join(),
+	}
+
+	// A value marked as secret by any loader is secret for all loaders.
+	merged := s.n.resolve(path)
+	secret := merged != nil && merged.secret
+
+	layers := s.layers
+	if s.overrides != nil {
+		layers = append(layers[:len(layers):len(layers)], s.overrides)
+	}
+
+	for i := len(layers) - 1; i >= 0; i-- {
+		n, removes := resolveDefinition(layers[i], path)
+		if n == nil {
+			continue
+		}
+
+		value := n.Value
+		if s.redactor().redacts(path, n) || (secret && n.Value != "") {
+			value = redacted
+		}
+
+		e.Definitions = append(e.Definitions, Definition{
+			Source:    n.source,
+			Value:     value,
+			Kind:      n.Kind(),
+			Effective: len(e.Definitions) == 0 && !removes,
+		})
+	}
+
+	return e
+}
+
+// resolveDefinition resolves path in the layer n. It stops at the first Node of kind KindUnset or KindNull
+// found along path. removes reports whether the returned Node removes the key, i.e. because it is of kind
+// KindUnset or a null parent of the key.
+func resolveDefinition(n *Node, path KeyPath) (found *Node, removes bool) {
+	for _, key := range path {
+		if n.kind == KindUnset || n.kind == KindNull {
+			return n, true
+		}
+		c, ok := n.Children[key]
+		if !ok {
+			return nil, false
+		}
+		n = c
+	}
+	return n, n.kind == KindUnset
+}
@@ -0,0 +1,98 @@
+package appconf
+
+import (
+	"testing"
+
+	"github.com/halimath/assertthat-go/assert"
+	"github.com/halimath/assertthat-go/is"
+)
+
+func TestAppConfig_Explain(t *testing.T) {
+	t.Setenv("EXPLAIN_DB_PORT", "3307")
+
+	c, err := New(
+		Static(map[string]interface{}{
+			"db.port": 1234,
+			"db.host": "example.com",
+		}),
+		YAMLFile("./testdata/config.yaml", true),
+		Env("EXPLAIN"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.Set("db.host", "db.example.com"); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.That(t, c.Explain("DB.Port"), is.DeepEqual(Explanation{
+		Key: "db.port",
+		Definitions: []Definition{
+			{Source: Source{Loader: "env"}, Value: "3307", Effective: true},
+			{Source: Source{Loader: "yaml", File: "./testdata/config.yaml", Line: 9, Column: 9}, Value: "3306", Kind: KindInt},
+			{Source: Source{Loader: "static"}, Value: "1234", Kind: KindInt},
+		},
+	}))
+
+	assert.That(t, c.Sub("db").Explain("host"), is.DeepEqual(Explanation{
+		Key: "host",
+		Definitions: []Definition{
+			{Source: Source{Loader: "override"}, Value: "db.example.com", Effective: true},
+			{Source: Source{Loader: "yaml", File: "./testdata/config.yaml", Line: 8, Column: 9}, Value: "localhost"},
+			{Source: Source{Loader: "static"}, Value: "example.com"},
+		},
+	}))
+
+	assert.That(t, c.Explain("db.unknown").Definitions, is.DeepEqual[[]Definition](nil))
+}
+
+func TestAppConfig_Explain_customLoader(t *testing.T) {
+	c, err := New(
+		LoaderFunc(func() (*Node, error) {
+			return ConvertToNode(map[string]interface{}{"foo": "bar"})
+		}),
+		Named("custom", LoaderFunc(func() (*Node, error) {
+			return ConvertToNode(map[string]interface{}{"foo": "spam"})
+		})),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.That(t, c.Explain("foo").String(), is.Equal("foo\n* custom: \"spam\"\n  loader #1: \"bar\""))
+}
+
+func TestAppConfig_Explain_unset(t *testing.T) {
+	c, err := New(
+		Static(map[string]interface{}{
+			"db.host": "localhost",
+		}),
+		Static(map[string]interface{}{
+			"db": Unset,
+		}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.That(t, c.Explain("db.host").String(), is.Equal("db.host\n  static: unset\n  static: \"localhost\""))
+}
+
+func TestAppConfig_Explain_nullParent(t *testing.T) {
+	c, err := New(
+		Static(map[string]interface{}{
+			"db.host": "x",
+		}),
+		Static(map[string]interface{}{
+			"db": nil,
+		}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.That(t, c.HasKey("db.host"), is.Equal(false))
+	assert.That(t, c.Explain("db.host").String(), is.Equal("db.host\n  static: null\n  static: \"x\""))
+	assert.That(t, c.Explain("db").String(), is.Equal("db\n* static: null\n  static"))
+}
@@ -0,0 +1,317 @@
+package appconf
+
+import (
+	"encoding"
+	"errors"
+	"flag"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Flags creates a Loader which reads configuration values from the flags defined in fs. Only flags that
+// have been set explicitly on the command line are considered (see flag.FlagSet.Visit), so the flags'
+// defaults do not overwrite values from less significant loaders. The flag names are used as keys, i.e.
+// the flag -db.host sets the key db.host. Flag values implementing flag.Getter keep their type.
+//
+// fs must have been parsed before the configuration is loaded.
+func Flags(fs *flag.FlagSet) Loader {
+	return LoaderFunc(func() (*Node, error) {
+		n := NewNode("")
+
+		var err error
+		fs.Visit(func(f *flag.Flag) {
+			if err != nil {
+				return
+			}
+
+			var v *Node
+			if g, ok := f.Value.(flag.Getter); ok {
+				v, err = createNodeFromValue(g.Get())
+			} else {
+				v = NewNode(f.Value.String())
+			}
+			if err == nil {
+				n.set(parseArgKey(f.Name), v)
+			}
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		n.annotate(Source{Loader: "flags"})
+		return n, nil
+	})
+}
+
+// argSet is the name of the argument introducing an override for Args.
+const argSet = "set"
+
+// Args creates a Loader which reads configuration values from the command line arguments args, such as
+// os.Args[1:]. Each value is given either as --key=value or as --set key=value (or --set=key=value). A
+// single dash may be used instead of two. A --key without a value sets key to true. Keys may address list
+// items using an index in brackets, i.e. --set servers[1].host=a sets the host of the second server. An index
+// beyond the end of a list extends the list when bound; missing items are bound as zero values.
+//
+// Parsing stops at the argument --. Any other argument not starting with a dash is reported as an error.
+func Args(args []string) Loader {
+	return LoaderFunc(func() (*Node, error) {
+		n := NewNode("")
+
+		for i := 0; i < len(args); i++ {
+			arg := args[i]
+			if arg == "--" {
+				break
+			}
+
+			name := strings.TrimPrefix(strings.TrimPrefix(arg, "-"), "-")
+			if name == arg || name == "" {
+				return nil, fmt.Errorf("%w: unexpected argument %q", ErrUnsupportedValue, arg)
+			}
+
+			key, value, hasValue := strings.Cut(name, "=")
+
+			if key == argSet {
+				if !hasValue {
+					i++
+					if i == len(args) {
+						return nil, fmt.Errorf("%w: missing value for %s", ErrUnsupportedValue, arg)
+					}
+					value = args[i]
+				}
+
+				override := value
+				key, value, hasValue = strings.Cut(override, "=")
+				if !hasValue || key == "" {
+					return nil, fmt.Errorf("%w: invalid override %q: expected key=value", ErrUnsupportedValue, override)
+				}
+			} else if !hasValue {
+				value = "true"
+			}
+
+			n.set(parseArgKey(key), NewNode(value))
+		}
+
+		n.annotate(Source{Loader: "args"})
+		return n, nil
+	})
+}
+
+// argIndexRegexp matches list indices given in brackets.
+var argIndexRegexp = regexp.MustCompile(`\[(\d+)\]`)
+
+// parseArgKey parses the key path s given on the command line. List indices may be given in brackets, i.e.
+// servers[1].host.
+func parseArgKey(s string) KeyPath {
+	return ParseKeyPath(argIndexRegexp.ReplaceAllString(s, KeySeparator+"$1"))
+}
+
+// DefineFlags defines a flag in fs for every config value bound to the struct v points to. Fields are
+// mapped to keys using the same rules as AppConfig.Bind, and the flags are named after the keys, i.e. the
+// field Timeout of the struct bound to db is defined as -db.timeout. Slices, arrays and maps are skipped.
+//
+// The usage text of a flag is taken from the field's description tag. The flag's default is the field's
+// current value or - if the field holds its zero value - the default given in the field's appconf tag.
+// Defaults of secret fields are displayed as ***.
+//
+// Values given for the flags are validated by converting them to the fields' types. Use Flags with fs to load
+// the values of all flags set on the command line:
+//
+//	var cfg Config
+//	fs := flag.NewFlagSet(os.Args[0], flag.ExitOnError)
+//	if err := appconf.DefineFlags(fs, &cfg); err != nil {
+//		...
+//	}
+//	fs.Parse(os.Args[1:])
+//
+//	c, err := appconf.New(appconf.YAMLFile("./config.yaml", true), appconf.Flags(fs))
+//	...
+//	err = c.Bind(&cfg)
+func DefineFlags(fs *flag.FlagSet, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return newBindError(reflect.TypeOf(v), nil, "", fmt.Errorf("%w: cannot define flags for %s", ErrInvalidBindingType, reflect.TypeOf(v)))
+	}
+
+	return defineStructFlags(fs, rv.Elem(), nil, "")
+}
+
+// defineStructFlags defines the flags for the fields of the struct rv bound to path. field is the Go field
+// path of rv.
+func defineStructFlags(fs *flag.FlagSet, rv reflect.Value, path KeyPath, field string) error {
+	rt := rv.Type()
+
+	for i := 0; i < rt.NumField(); i++ {
+		f := rt.Field(i)
+		fieldName := joinFieldPath(field, f.Name)
+
+		opts, err := determineBindOpts(f)
+		if err != nil {
+			return newBindError(f.Type, joinKeyPath(path, opts.key), fieldName, fmt.Errorf("%w: %s", ErrInvalidTag, err))
+		}
+
+		if opts.ignore {
+			continue
+		}
+
+		fv := rv.Field(i)
+		if opts.squash {
+			t := f.Type
+			if t.Kind() == reflect.Pointer {
+				t = t.Elem()
+			}
+			if t.Kind() != reflect.Struct {
+				return newBindError(f.Type, path, fieldName, fmt.Errorf("%w: %s requires a struct or an exported pointer to struct", ErrInvalidTag, FieldTagSquash))
+			}
+			if fv = reflect.Indirect(fv); !fv.IsValid() {
+				fv = reflect.Zero(t)
+			}
+			if err := defineStructFlags(fs, fv, path, fieldName); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if !f.IsExported() {
+			continue
+		}
+
+		fieldPath := joinKeyPath(path, opts.key)
+
+		if isNested(f.Type) {
+			t := f.Type
+			if t.Kind() == reflect.Pointer {
+				t = t.Elem()
+				if fv.IsNil() {
+					fv = reflect.Zero(t)
+				} else {
+					fv = fv.Elem()
+				}
+			}
+			if t.Kind() != reflect.Struct {
+				continue
+			}
+			if err := defineStructFlags(fs, fv, fieldPath, fieldName); err != nil {
+				return err
+			}
+			continue
+		}
+
+		name := fieldPath.Join()
+		if fs.Lookup(name) != nil {
+			return newBindError(f.Type, fieldPath, fieldName, fmt.Errorf("%w: flag already defined: -%s", ErrInvalidBindingType, name))
+		}
+
+		fl := &flagValue{
+			t:      f.Type,
+			path:   fieldPath,
+			secret: opts.secret || isSecretType(f.Type),
+		}
+		switch {
+		case !fv.IsZero():
+			fl.value = formatFlagValue(fv)
+		case opts.hasDefault:
+			fl.value = opts.defaultValue
+		}
+
+		fs.Var(fl, name, f.Tag.Get(FieldTagDescriptionKey))
+	}
+
+	return nil
+}
+
+// formatFlagValue formats the scalar value v the same way its config value is formatted.
+func formatFlagValue(v reflect.Value) string {
+	if v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return ""
+		}
+		if v.Kind() == reflect.Interface {
+			return formatFlagValue(v.Elem())
+		}
+	}
+
+	if n, err := createNodeFromValue(v.Interface()); err == nil {
+		return n.Value
+	}
+
+	if m, ok := v.Interface().(encoding.TextMarshaler); ok {
+		if text, err := m.MarshalText(); err == nil {
+			return string(text)
+		}
+	}
+
+	return fmt.Sprint(reflect.Indirect(v).Interface())
+}
+
+// flagValue implements flag.Getter for a flag defined by DefineFlags. Values are validated by converting
+// them to the type of the field the flag has been defined for.
+type flagValue struct {
+	// t is the type of the field.
+	t reflect.Type
+	// path is the key path the field is bound to.
+	path KeyPath
+	// secret signals that the flag's value must not be revealed.
+	secret bool
+	// value is the flag's value as given on the command line or its default.
+	value string
+	// typed is the value converted to a type supported by Node or nil, if the value is kept as a string.
+	typed interface{}
+}
+
+func (v *flagValue) String() string {
+	if v == nil {
+		return ""
+	}
+	if v.secret && v.value != "" {
+		return redacted
+	}
+	return v.value
+}
+
+func (v *flagValue) Set(s string) error {
+	converted, err := bindValue(NewNode(s), v.t, v.path, redactor{})
+	if err != nil {
+		var bindErr *BindError
+		if errors.As(err, &bindErr) {
+			err = bindErr.Err
+		}
+		var numErr *strconv.NumError
+		if errors.As(err, &numErr) {
+			err = numErr.Err
+		}
+		return err
+	}
+
+	v.value, v.typed = s, nil
+	if converted.IsValid() && !v.secret {
+		if _, err := createNodeFromValue(converted.Interface()); err == nil {
+			v.typed = converted.Interface()
+		}
+	}
+	return nil
+}
+
+// Get returns the flag's value in a form accepted by Flags.
+func (v *flagValue) Get() interface{} {
+	switch {
+	case v.secret:
+		return Secret(v.value)
+	case v.typed != nil:
+		return v.typed
+	default:
+		return v.value
+	}
+}
+
+// IsBoolFlag reports whether the flag has been defined for a bool field and may be given without a value.
+func (v *flagValue) IsBoolFlag() bool {
+	t := v.t
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	_, hasDecoder := decoderFor(t)
+	return t.Kind() == reflect.Bool && !hasDecoder
+}
@@ -0,0 +1,263 @@
+package appconf
+
+import (
+	"errors"
+	"flag"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/halimath/assertthat-go/assert"
+	"github.com/halimath/assertthat-go/is"
+)
+
+func TestFlags(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.String("db.host", "flaghost", "")
+	fs.Int("db.port", 1234, "")
+	fs.Duration("web.timeout", time.Second, "")
+	fs.Bool("web.authorize", false, "")
+	fs.String("backends[1].host", "", "")
+
+	if err := fs.Parse([]string{"-db.port=5432", "-web.timeout", "5s", "-web.authorize", "-backends[1].host=gamma"}); err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := New(JSONFile("./testdata/config.json", true), Flags(fs))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.That(t, c.GetString("db.host"), is.Equal("localhost"))
+	assert.That(t, c.GetInt("db.port"), is.Equal(5432))
+	assert.That(t, c.GetDuration("web.timeout"), is.Equal(5*time.Second))
+	assert.That(t, c.GetBool("web.authorize"), is.Equal(true))
+	assert.That(t, c.GetString("backends.0.host"), is.Equal("alpha"))
+	assert.That(t, c.GetString("backends.1.host"), is.Equal("gamma"))
+	assert.That(t, c.GetInt("backends.1.port"), is.Equal(8081))
+	assert.That(t, c.Explain("db.port").Definitions[0].Source, is.Equal(Source{Loader: "flags"}))
+}
+
+func TestArgs(t *testing.T) {
+	c, err := New(JSONFile("./testdata/config.json", true), Args([]string{
+		"--db.host=dbhost",
+		"--set", "db.port=5432",
+		"-set=web.timeout=5s",
+		"--web.authorize",
+		"--set", "backends[1].tags[0]=c",
+		"--set", "backends[1].host=a=b",
+		"--",
+		"--db.user=ignored",
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.That(t, c.GetString("db.host"), is.Equal("dbhost"))
+	assert.That(t, c.GetInt("db.port"), is.Equal(5432))
+	assert.That(t, c.GetDuration("web.timeout"), is.Equal(5*time.Second))
+	assert.That(t, c.GetBool("web.authorize"), is.Equal(true))
+	assert.That(t, c.GetString("db.user"), is.Equal("test"))
+	assert.That(t, c.GetString("backends.1.host"), is.Equal("a=b"))
+	assert.That(t, c.GetString("backends.1.tags.0"), is.Equal("c"))
+	assert.That(t, c.GetString("backends.1.tags.1"), is.Equal("2"))
+	assert.That(t, c.GetString("backends.0.host"), is.Equal("alpha"))
+}
+
+func TestArgs_bindLists(t *testing.T) {
+	type Server struct {
+		Host string
+		Port int
+	}
+
+	type Config struct {
+		Servers []Server
+		Tags    []string
+		Ports   [3]int
+	}
+
+	tests := map[string]struct {
+		base map[string]interface{}
+		args []string
+		want Config
+	}{
+		"no base list": {
+			base: map[string]interface{}{},
+			args: []string{"--set", "servers[1].host=a", "--tags[0]=x", "--ports[2]=80"},
+			want: Config{
+				Servers: []Server{{}, {Host: "a"}},
+				Tags:    []string{"x"},
+				Ports:   [3]int{0, 0, 80},
+			},
+		},
+		"override item": {
+			base: map[string]interface{}{
+				"servers": []interface{}{
+					map[string]interface{}{"host": "x", "port": 1},
+					map[string]interface{}{"host": "y", "port": 2},
+				},
+			},
+			args: []string{"--set", "servers[1].host=a"},
+			want: Config{
+				Servers: []Server{{Host: "x", Port: 1}, {Host: "a", Port: 2}},
+			},
+		},
+		"beyond base list": {
+			base: map[string]interface{}{
+				"servers": []interface{}{
+					map[string]interface{}{"host": "x"},
+				},
+				"tags": []interface{}{"a"},
+			},
+			args: []string{"--set", "servers[2].host=a", "--set", "tags[1]=b"},
+			want: Config{
+				Servers: []Server{{Host: "x"}, {}, {Host: "a"}},
+				Tags:    []string{"a", "b"},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			c, err := New(Static(test.base), Args(test.args))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got Config
+			if err := c.Bind(&got); err != nil {
+				t.Fatal(err)
+			}
+			assert.That(t, got, is.DeepEqual(test.want))
+		})
+	}
+}
+
+func TestArgs_errors(t *testing.T) {
+	tests := map[string][]string{
+		`unsupported value: unexpected argument "positional"`:               {"--db.host=x", "positional"},
+		`unsupported value: unexpected argument "-"`:                        {"-"},
+		"unsupported value: missing value for --set":                        {"--set"},
+		`unsupported value: invalid override "db.host": expected key=value`: {"--set", "db.host"},
+		`unsupported value: invalid override "=x": expected key=value`:      {"--set==x"},
+	}
+
+	for want, args := range tests {
+		_, err := Args(args).Load()
+		assert.That(t, errors.Is(err, ErrUnsupportedValue), is.Equal(true))
+		assert.That(t, err.Error(), is.Equal(want))
+	}
+}
+
+func TestDefineFlags(t *testing.T) {
+	type Common struct {
+		Name string `description:"application name"`
+	}
+
+	type config struct {
+		Common `appconf:",squash"`
+		DB     struct {
+			Host     string        `description:"database host"`
+			Port     int           `appconf:",default=3306"`
+			Password Secret        `description:"database password"`
+			Timeout  time.Duration `description:"connection timeout"`
+		}
+		Web struct {
+			Authorize bool
+		}
+		Backends []struct {
+			Host string
+		}
+		Ignored string `appconf:",ignore"`
+		Debug   *bool
+	}
+
+	var cfg config
+	cfg.Name = "app"
+	cfg.DB.Host = "localhost"
+	cfg.DB.Password = "secret"
+	cfg.DB.Timeout = 2 * time.Second
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	if err := DefineFlags(fs, &cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+	fs.VisitAll(func(f *flag.Flag) {
+		names = append(names, f.Name+"="+f.DefValue+" "+f.Usage)
+	})
+	assert.That(t, names, is.DeepEqual([]string{
+		"db.host=localhost database host",
+		"db.password=*** database password",
+		"db.port=3306 ",
+		"db.timeout=2s connection timeout",
+		"debug= ",
+		"name=app application name",
+		"web.authorize= ",
+	}))
+
+	if err := fs.Parse([]string{"-db.port=5432", "-db.timeout=5s", "-db.password", "flagsecret", "-web.authorize", "-debug"}); err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := New(Static(map[string]interface{}{
+		"name": "static",
+		"db": map[string]interface{}{
+			"port":    3307,
+			"timeout": "1s",
+		},
+	}), Flags(fs))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got config
+	if err := c.Bind(&got); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.That(t, got.Name, is.Equal("static"))
+	assert.That(t, got.DB.Host, is.Equal(""))
+	assert.That(t, got.DB.Port, is.Equal(5432))
+	assert.That(t, got.DB.Password, is.Equal(Secret("flagsecret")))
+	assert.That(t, got.DB.Timeout, is.Equal(5*time.Second))
+	assert.That(t, got.Web.Authorize, is.Equal(true))
+	assert.That(t, *got.Debug, is.Equal(true))
+
+	assert.That(t, c.Explain("db.password").String(), is.Equal("db.password\n* flags: \"***\""))
+}
+
+func TestDefineFlags_invalidValue(t *testing.T) {
+	var cfg struct {
+		Port int
+	}
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	if err := DefineFlags(fs, &cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	err := fs.Parse([]string{"-port=x"})
+	assert.That(t, err.Error(), is.Equal(`invalid value "x" for flag -port: invalid syntax`))
+}
+
+func TestDefineFlags_errors(t *testing.T) {
+	var invalid struct {
+		Name int `appconf:",squash"`
+	}
+	err := DefineFlags(flag.NewFlagSet("test", flag.ContinueOnError), &invalid)
+	assert.That(t, errors.Is(err, ErrInvalidTag), is.Equal(true))
+
+	var cfg struct {
+		Name string
+	}
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.String("name", "", "")
+	err = DefineFlags(fs, &cfg)
+	assert.That(t, err.Error(), is.Equal("name: invalid binding type: flag already defined: -name (field Name)"))
+
+	err = DefineFlags(fs, cfg)
+	assert.That(t, errors.Is(err, ErrInvalidBindingType), is.Equal(true))
+}
@@ -0,0 +1,58 @@
+package appconf
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Get returns the value stored under key converted to T. T can be any type supported by Bind, including
+// structs, slices and map[string]interface{}. The conversion works the same way as binding a struct field
+// of type T. Get returns an error wrapping ErrNoSuchKey if key is not defined and T's zero value if the value
+// is null.
+func Get[T any](c *AppConfig, key string) (T, error) {
+	var v T
+
+	n, err := c.get(key)
+	if err != nil {
+		return v, err
+	}
+
+	return convertTo[T](n, key, c.redactor())
+}
+
+// GetOr works like Get but returns def if key is not defined or its value is null. Errors converting the
+// value are reported just as with Get.
+func GetOr[T any](c *AppConfig, key string, def T) (T, error) {
+	n, err := c.get(key)
+	if err != nil || n.kind == KindNull {
+		return def, nil
+	}
+
+	return convertTo[T](n, key, c.redactor())
+}
+
+// MustGet works like Get but panics if the value cannot be returned.
+func MustGet[T any](c *AppConfig, key string) T {
+	v, err := Get[T](c, key)
+	if err != nil {
+		panic(fmt.Sprintf("appconf: %s", err))
+	}
+	return v
+}
+
+// convertTo converts n which is stored under key to a value of type T. Errors caused by values r considers
+// secret are redacted.
+func convertTo[T any](n *Node, key string, r redactor) (T, error) {
+	var v T
+	if n.kind == KindNull {
+		return v, nil
+	}
+
+	rv, err := bindValue(n, reflect.TypeOf(&v).Elem(), ParseKeyPath(key), r)
+	if err != nil {
+		return v, err
+	}
+
+	reflect.ValueOf(&v).Elem().Set(rv)
+	return v, nil
+}
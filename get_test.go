@@ -0,0 +1,118 @@
+package appconf
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/halimath/assertthat-go/assert"
+	"github.com/halimath/assertthat-go/is"
+)
+
+func TestGet(t *testing.T) {
+	type DB struct {
+		Engine string `appconf:"type"`
+		Host   string
+		Port   uint16
+	}
+
+	c, err := New(JSONFile("./testdata/config.json", true), Static(map[string]interface{}{
+		"web": map[string]interface{}{
+			"ports": []interface{}{80, 443},
+		},
+		"nothing": nil,
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	port, err := Get[int](c, "db.port")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.That(t, port, is.Equal(3306))
+
+	timeout, err := Get[time.Duration](c, "web.timeout")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.That(t, timeout, is.Equal(2*time.Second))
+
+	db, err := Get[DB](c, "db")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.That(t, db, is.Equal(DB{Engine: "mysql", Host: "localhost", Port: 3306}))
+
+	ports, err := Get[[]int](c, "web.ports")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.That(t, ports, is.DeepEqual([]int{80, 443}))
+
+	m, err := Get[map[string]interface{}](c, "db")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.That(t, m["port"], is.DeepEqual[interface{}](int64(3306)))
+
+	nothing, err := Get[string](c, "nothing")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.That(t, nothing, is.Equal(""))
+
+	_, err = Get[int](c, "db.missing")
+	assert.That(t, errors.Is(err, ErrNoSuchKey), is.Equal(true))
+
+	_, err = Get[int](c, "db.host")
+	assert.That(t, errors.Is(err, ErrInvalidValue), is.Equal(true))
+
+	_, err = Get[int8](c, "db.port")
+	assert.That(t, errors.Is(err, strconv.ErrRange), is.Equal(true))
+}
+
+func TestGetOr(t *testing.T) {
+	c, err := New(JSONFile("./testdata/config.json", true), Static(map[string]interface{}{
+		"nothing": nil,
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	port, err := GetOr(c, "db.port", 5432)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.That(t, port, is.Equal(3306))
+
+	port, err = GetOr(c, "db.missing", 5432)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.That(t, port, is.Equal(5432))
+
+	s, err := GetOr(c, "nothing", "default")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.That(t, s, is.Equal("default"))
+
+	_, err = GetOr(c, "db.host", 5432)
+	assert.That(t, errors.Is(err, ErrInvalidValue), is.Equal(true))
+}
+
+func TestMustGet(t *testing.T) {
+	c, err := New(JSONFile("./testdata/config.json", true))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.That(t, MustGet[string](c, "db.host"), is.Equal("localhost"))
+
+	defer func() {
+		assert.That(t, recover() != nil, is.Equal(true))
+	}()
+	MustGet[int](c, "db.missing")
+}
@@ -0,0 +1,80 @@
+package appconf
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// INI parses the content of r in INI format and converts it to a Node tree. Keys defined in a section are
+// nested under the section's name; a section header such as [db.replica] defines nested sections. Keys defined
+// before the first section header are stored at the root. Dotted keys are mapped to nested keys as well.
+//
+// Key and value are separated by = or :. Lines starting with ; or # are comments; unquoted values may be
+// followed by a comment starting with ; or # preceded by whitespace. Values may be enclosed in double quotes to
+// retain surrounding whitespace and comment characters. A line ending with a backslash is continued on the
+// next line. Values may contain the escape sequences \t, \n, \r, \f, \uXXXX as well as \\, \;, \#, \", \= and
+// \:; other backslashes are kept as is. Syntax errors are reported as a *ParseError.
+func INI(r io.Reader) (*Node, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	n := NewNode("")
+	var section KeyPath
+
+	for _, l := range readLogicalLines(string(data), ";#") {
+		text := strings.TrimSpace(l.text)
+
+		if strings.HasPrefix(text, "[") {
+			name := strings.TrimSpace(strings.TrimSuffix(text[1:], "]"))
+			if !strings.HasSuffix(text, "]") || name == "" {
+				return nil, l.error(strings.TrimLeft(l.text, " \t\f"), fmt.Errorf("%w: invalid section header", ErrUnsupportedValue))
+			}
+			section = ParseKeyPath(name)
+
+			s := NewNode("")
+			s.source.Line, s.source.Column = l.line, l.column(len(l.text)-len(strings.TrimLeft(l.text, " \t\f")))
+			n.set(section, s)
+			continue
+		}
+
+		sep := strings.IndexAny(text, "=:")
+		if sep < 0 {
+			return nil, l.error(strings.TrimLeft(l.text, " \t\f"), fmt.Errorf("%w: missing separator", ErrUnsupportedValue))
+		}
+
+		key := strings.TrimSpace(text[:sep])
+		if key == "" {
+			return nil, l.error(strings.TrimLeft(l.text, " \t\f"), fmt.Errorf("%w: missing key", ErrUnsupportedValue))
+		}
+
+		rawValue := strings.TrimLeft(text[sep+1:], " \t\f")
+		offset := len(strings.TrimRight(l.text, " \t\f")) - len(rawValue)
+
+		if len(rawValue) >= 2 && rawValue[0] == '"' && rawValue[len(rawValue)-1] == '"' {
+			rawValue = rawValue[1 : len(rawValue)-1]
+		} else {
+			rawValue = cutComment(rawValue, ";#")
+		}
+
+		value, err := unescapeProperty(rawValue, true)
+		if err != nil {
+			return nil, l.error(l.text[offset:], err)
+		}
+
+		v := NewNode(value)
+		v.source.Line, v.source.Column = l.line, l.column(offset)
+		n.set(append(section[:len(section):len(section)], ParseKeyPath(key)...), v)
+	}
+
+	n.annotate(Source{Loader: "ini"})
+	return n, nil
+}
+
+// INIFile creates a Loader which loads configuration from the INI file name. See INI for a description of
+// the format.
+func INIFile(name string, mandatory bool) Loader {
+	return File(name, mandatory, INI)
+}
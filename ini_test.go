@@ -0,0 +1,65 @@
+package appconf
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/halimath/assertthat-go/assert"
+	"github.com/halimath/assertthat-go/is"
+)
+
+func TestINI(t *testing.T) {
+	got, err := INI(strings.NewReader(`; global settings
+name = test
+
+[db]
+host = localhost
+port: 5432   ; default port
+password = "p;ss # word "
+path = C:\data\db
+
+[db.replica]
+host = replica.\
+  example.com
+greeting = Gr\u00fc\u00dfe
+
+[empty]
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := &AppConfig{n: got}
+	assert.That(t, c.GetString("name"), is.Equal("test"))
+	assert.That(t, c.GetString("db.host"), is.Equal("localhost"))
+	assert.That(t, c.GetInt("db.port"), is.Equal(5432))
+	assert.That(t, c.GetString("db.password"), is.Equal("p;ss # word "))
+	assert.That(t, c.GetString("db.path"), is.Equal(`C:\data\db`))
+	assert.That(t, c.GetString("db.replica.host"), is.Equal("replica.example.com"))
+	assert.That(t, c.GetString("db.replica.greeting"), is.Equal("Grüße"))
+	assert.That(t, c.HasKey("empty"), is.Equal(true))
+
+	assert.That(t, got.resolve(ParseKeyPath("db")).Source(), is.Equal(Source{Loader: "ini", Line: 4, Column: 1}))
+	assert.That(t, got.resolve(ParseKeyPath("db.port")).Source(), is.Equal(Source{Loader: "ini", Line: 6, Column: 7}))
+}
+
+func TestINI_errors(t *testing.T) {
+	tests := map[string]string{
+		"[db\nhost = localhost\n":  "line 1, column 1: unsupported value: invalid section header",
+		"[db]\n  host localhost\n": "line 2, column 3: unsupported value: missing separator",
+		"[db]\n= localhost\n":      "line 2, column 1: unsupported value: missing key",
+	}
+
+	for content, want := range tests {
+		_, err := INI(strings.NewReader(content))
+
+		var parseErr *ParseError
+		assert.That(t, errors.As(err, &parseErr), is.Equal(true))
+		assert.That(t, err.Error(), is.Equal(want))
+	}
+}
+
+func TestINIFile(t *testing.T) {
+	assertLoader(t, INIFile("./testdata/config.ini", true))
+}
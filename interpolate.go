@@ -0,0 +1,238 @@
+package appconf
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// ErrInterpolation is reported when a reference contained in a config value cannot be resolved.
+var ErrInterpolation = errors.New("interpolation failed")
+
+const (
+	// interpolationEnvPrefix is the prefix of references to environment variables.
+	interpolationEnvPrefix = "env:"
+	// interpolationDefaultSeparator separates a reference from its default value.
+	interpolationDefaultSeparator = ":-"
+)
+
+// WithoutInterpolation creates an Option that disables the resolution of references contained in config
+// values. Values are returned exactly as they have been loaded.
+func WithoutInterpolation() Option {
+	return func(c *AppConfig) {
+		c.noInterpolation = true
+	}
+}
+
+// interpolate resolves the references contained in the string values of the tree rooted at root. root is
+// modified in place.
+//
+// A reference of the form ${key.path} is replaced with the value stored under key.path, a reference of the
+// form ${env:VAR} with the value of the environment variable VAR. If key.path is not defined, ${key.path} is
+// replaced with the value of the environment variable named key.path (as given), so ${HOME} works as in the
+// shell. Both forms accept a default value given as ${key.path:-default} which is used if the referenced value
+// is not defined or empty. Referenced values may
+// contain references themselves; cyclic references are reported as errors. $${ produces a literal ${.
+// Values marked as literal, such as values read from environment variables, are not modified.
+//
+// A value consisting of a single reference to a key receives the kind of the referenced value. Values
+// referencing a secret value or a key marked as secret by r are marked as secret themselves.
+func interpolate(root *Node, r redactor) error {
+	in := interpolator{
+		root:    root,
+		secrets: r,
+		state:   make(map[string]interpolationState),
+	}
+	return in.walk(root, nil)
+}
+
+type interpolationState int
+
+const (
+	unresolved interpolationState = iota
+	resolving
+	resolved
+)
+
+// interpolator implements the resolution of references. It tracks the state of each value to resolve every
+// value only once and to detect cycles.
+type interpolator struct {
+	root    *Node
+	secrets redactor
+	state   map[string]interpolationState
+	// stack contains the keys of the values currently being resolved.
+	stack []string
+}
+
+// walk resolves the references in n, which is stored under path, and all its descendants.
+func (in *interpolator) walk(n *Node, path KeyPath) error {
+	switch n.Kind() {
+	case KindMap, KindList:
+		for _, k := range n.sortedKeys() {
+			if err := in.walk(n.Children[k], append(path[:len(path):len(path)], k)); err != nil {
+				return err
+			}
+		}
+		return nil
+	case KindString:
+		return in.resolve(n, path)
+	default:
+		return nil
+	}
+}
+
+// resolve resolves the references in the scalar value n stored under path.
+func (in *interpolator) resolve(n *Node, path KeyPath) error {
+	key := path.Join()
+
+	switch in.state[key] {
+	case resolved:
+		return nil
+	case resolving:
+		cycle := append(in.stack[indexOf(in.stack, key):len(in.stack):len(in.stack)], key)
+		return interpolationError(n, key, "cyclic reference: "+strings.Join(cycle, " -> "))
+	}
+
+	if n.kind != KindString || n.literal || !strings.Contains(n.Value, "$") {
+		in.state[key] = resolved
+		return nil
+	}
+
+	in.state[key] = resolving
+	in.stack = append(in.stack, key)
+
+	err := in.expand(n, key)
+
+	in.stack = in.stack[:len(in.stack)-1]
+	in.state[key] = resolved
+
+	return err
+}
+
+// expand replaces the references in n's value. key is the key n is stored under.
+func (in *interpolator) expand(n *Node, key string) error {
+	var (
+		b     strings.Builder
+		s     = n.Value
+		whole *Node
+	)
+
+	for {
+		i := strings.IndexByte(s, '$')
+		if i < 0 {
+			b.WriteString(s)
+			break
+		}
+		b.WriteString(s[:i])
+		s = s[i:]
+
+		switch {
+		case strings.HasPrefix(s, "$${"):
+			b.WriteString("${")
+			s = s[3:]
+
+		case strings.HasPrefix(s, "${"):
+			end := strings.IndexByte(s, '}')
+			if end < 0 {
+				return interpolationError(n, key, fmt.Sprintf("unterminated reference %q", s))
+			}
+
+			v, err := in.lookup(s[2:end], n, key)
+			if err != nil {
+				return err
+			}
+			if v != nil && b.Len() == 0 && end+1 == len(s) && v.kind != KindString {
+				whole = v
+			}
+
+			if v != nil {
+				b.WriteString(v.Value)
+				n.secret = n.secret || v.secret
+			} else {
+				_, def, _ := strings.Cut(s[2:end], interpolationDefaultSeparator)
+				b.WriteString(def)
+			}
+			s = s[end+1:]
+
+		default:
+			b.WriteByte('$')
+			s = s[1:]
+		}
+	}
+
+	n.Value = b.String()
+	if whole != nil {
+		n.kind = whole.kind
+		n.raw = whole.raw
+	}
+	return nil
+}
+
+// lookup resolves the reference expr found in the value n stored under key. It returns the referenced Node or
+// nil, if the reference's default value is to be used.
+func (in *interpolator) lookup(expr string, n *Node, key string) (*Node, error) {
+	name, _, hasDefault := strings.Cut(expr, interpolationDefaultSeparator)
+
+	if strings.HasPrefix(name, interpolationEnvPrefix) {
+		v, ok := os.LookupEnv(strings.TrimPrefix(name, interpolationEnvPrefix))
+		switch {
+		case hasDefault && v == "":
+			return nil, nil
+		case !ok:
+			return nil, interpolationError(n, key, fmt.Sprintf("environment variable %s is not set", strings.TrimPrefix(name, interpolationEnvPrefix)))
+		}
+		return NewNode(v), nil
+	}
+
+	path := ParseKeyPath(name)
+	ref := in.root.resolve(path)
+	if ref == nil {
+		// Fall back to the environment variable name like the shell does for ${VAR:-default}.
+		if v, ok := os.LookupEnv(name); ok && (v != "" || !hasDefault) {
+			return NewNode(v), nil
+		}
+	}
+	if ref == nil || ref.kind == KindNull {
+		if hasDefault {
+			return nil, nil
+		}
+		return nil, interpolationError(n, key, "undefined key "+path.Join())
+	}
+
+	if k := ref.Kind(); k == KindMap || k == KindList {
+		return nil, interpolationError(n, key, "referenced key "+path.Join()+" is not a scalar")
+	}
+
+	if err := in.resolve(ref, path); err != nil {
+		return nil, err
+	}
+
+	if hasDefault && ref.Value == "" {
+		return nil, nil
+	}
+	if in.secrets.isSecretKey(path) {
+		n.secret = true
+	}
+	return ref, nil
+}
+
+// interpolationError creates an error describing the failed resolution of the value n stored under key.
+// The error is prefixed with n's position, if known.
+func interpolationError(n *Node, key, msg string) error {
+	err := fmt.Errorf("%w: %s: %s", ErrInterpolation, key, msg)
+	if l := n.source.location(); l != "" {
+		return fmt.Errorf("%s: %w", l, err)
+	}
+	return err
+}
+
+// indexOf returns the index of the first occurrence of s in l or -1.
+func indexOf(l []string, s string) int {
+	for i, e := range l {
+		if e == s {
+			return i
+		}
+	}
+	return -1
+}
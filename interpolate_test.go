@@ -0,0 +1,184 @@
+package appconf
+
+import (
+	"bytes"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/halimath/assertthat-go/assert"
+	"github.com/halimath/assertthat-go/is"
+)
+
+func TestInterpolation(t *testing.T) {
+	t.Setenv("APPCONF_TEST_DB_URL", "postgres://db/app")
+	t.Setenv("APPCONF_TEST_EMPTY", "")
+
+	c, err := New(
+		Static(map[string]interface{}{
+			"web.host":  "localhost",
+			"web.port":  8080,
+			"web.url":   "http://${web.host}:${web.port}",
+			"web.root":  "${web.url}/",
+			"web.proxy": "${web.port}",
+			"db.dsn":    "${env:APPCONF_TEST_DB_URL}",
+			"db.user":   "${env:APPCONF_TEST_EMPTY:-postgres}",
+			"db.pool":   "${db.size:-10}",
+			"price":     "$5 and $${literal}",
+			"servers":   []interface{}{"${web.host}"},
+		}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.That(t, c.GetString("web.url"), is.Equal("http://localhost:8080"))
+	assert.That(t, c.GetString("web.root"), is.Equal("http://localhost:8080/"))
+	assert.That(t, c.GetString("db.dsn"), is.Equal("postgres://db/app"))
+	assert.That(t, c.GetString("db.user"), is.Equal("postgres"))
+	assert.That(t, c.GetInt("db.pool"), is.Equal(10))
+	assert.That(t, c.GetString("price"), is.Equal("$5 and ${literal}"))
+	assert.That(t, c.GetStringSlice("servers"), is.DeepEqual([]string{"localhost"}))
+
+	proxy, err := c.get("web.proxy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.That(t, proxy.Kind(), is.Equal(KindInt))
+
+	if err := c.Set("web.host", "example.com"); err != nil {
+		t.Fatal(err)
+	}
+	assert.That(t, c.GetString("web.url"), is.Equal("http://example.com:8080"))
+}
+
+func TestInterpolation_secrets(t *testing.T) {
+	c, err := NewWithOptions([]Loader{
+		Static(map[string]interface{}{
+			"db.password": "hunter2",
+			"db.token":    Secret("t0k3n"),
+			"db.host":     "h",
+			"db.dsn":      "pg://u:${db.password}@${db.host}",
+			"db.auth":     "Bearer ${db.token}",
+			"db.url":      "pg://${db.host}",
+		}),
+	}, WithSecretKeys("db.password"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var b bytes.Buffer
+	if err := c.WriteJSON(&b); err != nil {
+		t.Fatal(err)
+	}
+
+	got := b.String()
+	assert.That(t, strings.Contains(got, `"dsn": "***"`), is.Equal(true))
+	assert.That(t, strings.Contains(got, `"auth": "***"`), is.Equal(true))
+	assert.That(t, strings.Contains(got, `"url": "pg://h"`), is.Equal(true))
+	assert.That(t, strings.Contains(got, "hunter2"), is.Equal(false))
+	assert.That(t, c.GetString("db.dsn"), is.Equal("pg://u:hunter2@h"))
+}
+
+func TestInterpolation_env(t *testing.T) {
+	t.Setenv("APPCONF_TEST_UNTERMINATED", "a${b")
+	t.Setenv("APPCONF_TEST_REFERENCE", "${web.host}")
+	t.Setenv("APPCONF_TEST_TOKEN", "s3${cr}3t")
+
+	c, err := New(
+		Static(map[string]interface{}{
+			"web.host": "localhost",
+			"web.url":  "http://${appconf.test.reference}",
+		}),
+		Env(""),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.That(t, c.GetString("appconf.test.unterminated"), is.Equal("a${b"))
+	assert.That(t, c.GetString("appconf.test.reference"), is.Equal("${web.host}"))
+	assert.That(t, c.GetString("appconf.test.token"), is.Equal("s3${cr}3t"))
+	assert.That(t, c.GetString("web.url"), is.Equal("http://${web.host}"))
+}
+
+func TestInterpolation_envFallback(t *testing.T) {
+	t.Setenv("APPCONF_TEST_HOME", "/home/test")
+	t.Setenv("APPCONF_TEST_EMPTY", "")
+
+	c, err := New(Static(map[string]interface{}{
+		"web.host": "localhost",
+		"home":     "${APPCONF_TEST_HOME:-/tmp}",
+		"plain":    "${APPCONF_TEST_HOME}",
+		"empty":    "${APPCONF_TEST_EMPTY:-default}",
+		"unset":    "${APPCONF_TEST_UNDEFINED:-/tmp}",
+		"key":      "${web.host}",
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.That(t, c.GetString("home"), is.Equal("/home/test"))
+	assert.That(t, c.GetString("plain"), is.Equal("/home/test"))
+	assert.That(t, c.GetString("empty"), is.Equal("default"))
+	assert.That(t, c.GetString("unset"), is.Equal("/tmp"))
+	assert.That(t, c.GetString("key"), is.Equal("localhost"))
+}
+
+func TestInterpolation_errors(t *testing.T) {
+	tests := map[string]struct {
+		values map[string]interface{}
+		want   string
+	}{
+		"undefined key": {
+			map[string]interface{}{"a": "${b}"},
+			"interpolation failed: a: undefined key b",
+		},
+		"undefined env": {
+			map[string]interface{}{"a": "${env:APPCONF_TEST_UNDEFINED}"},
+			"interpolation failed: a: environment variable APPCONF_TEST_UNDEFINED is not set",
+		},
+		"not a scalar": {
+			map[string]interface{}{"a": "${b}", "b.c": "d"},
+			"interpolation failed: a: referenced key b is not a scalar",
+		},
+		"unterminated": {
+			map[string]interface{}{"a": "${b"},
+			`interpolation failed: a: unterminated reference "${b"`,
+		},
+		"cycle": {
+			map[string]interface{}{"a": "${b}", "b": "x${c}", "c": "${a}"},
+			"interpolation failed: a: cyclic reference: a -> b -> c -> a",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := New(Static(test.values))
+			assert.That(t, errors.Is(err, ErrInterpolation), is.Equal(true))
+			assert.That(t, err.Error(), is.Equal(test.want))
+		})
+	}
+}
+
+func TestInterpolation_position(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "config.yaml")
+	writeFile(t, filename, "db:\n  dsn: ${db.url}\n")
+
+	_, err := New(YAMLFile(filename, true))
+	assert.That(t, err.Error(), is.Equal(filename+":2:8: interpolation failed: db.dsn: undefined key db.url"))
+}
+
+func TestWithoutInterpolation(t *testing.T) {
+	c, err := NewWithOptions([]Loader{
+		Static(map[string]interface{}{
+			"a": "${b}",
+		}),
+	}, WithoutInterpolation())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.That(t, c.GetString("a"), is.Equal("${b}"))
+}
@@ -1,10 +1,14 @@
 package appconf
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"os"
+	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/BurntSushi/toml"
@@ -18,6 +22,24 @@ type Loader interface {
 	Load() (*Node, error)
 }
 
+// ParseError describes an error parsing configuration data. Source locates the error as precise as known to the
+// decoder; the file is added by the File loader.
+type ParseError struct {
+	Source Source
+	Err    error
+}
+
+func (e *ParseError) Error() string {
+	if l := e.Source.location(); l != "" {
+		return fmt.Sprintf("%s: %s", l, e.Err)
+	}
+	return e.Err.Error()
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
 // LoaderFunc is a convenience type to convert a function to a Loader.
 type LoaderFunc func() (*Node, error)
 
@@ -29,45 +51,242 @@ func (l LoaderFunc) Load() (*Node, error) {
 type ReaderLoaderFunc func(io.Reader) (*Node, error)
 
 // Static creates a Loader that returns static configuration values from the given map structure. The map's
-// values are limited to strings, map[string]interface{} (with the same value constraints applied) or slices
-// of either strings or maps.
+// values are limited to scalar values, maps with string keys (with the same value constraints applied) and
+// slices or arrays of such values.
 func Static(m map[string]interface{}) Loader {
 	return LoaderFunc(func() (*Node, error) {
-		return ConvertToNode(m)
+		n, err := ConvertToNode(m)
+		if err != nil {
+			return nil, err
+		}
+		n.annotate(Source{Loader: "static"})
+		return n, nil
 	})
 }
 
-// File creates a Loader that reads the file named filename and forwards the content to l. If mandatory is
-// set to false, an empty configuration will be returned when filename does not exist. Otherwise this is is
-// reported as an error.
-func File(filename string, mandatory bool, l ReaderLoaderFunc) Loader {
+// Named creates a Loader that sets name as the loader name of the sources of all Nodes loaded by l. Use it to
+// give custom loaders a descriptive name used when explaining configuration values (see AppConfig.Explain).
+func Named(name string, l Loader) Loader {
 	return LoaderFunc(func() (*Node, error) {
-		f, err := os.Open(filename)
+		n, err := l.Load()
 		if err != nil {
-			if errors.Is(err, os.ErrNotExist) && !mandatory {
-				return NewNode(""), nil
-			}
 			return nil, err
 		}
-		defer f.Close()
-
-		return l(f)
+		n.walk(func(n *Node) {
+			n.source.Loader = name
+		})
+		return n, nil
 	})
 }
 
+// FileLoader is implemented by Loaders that read their values from a single file. AppConfig.Watch uses this
+// interface to determine the files to poll for changes.
+type FileLoader interface {
+	Loader
+
+	// Filename returns the name of the file this loader reads.
+	Filename() string
+}
+
+// File creates a Loader that reads the file named filename and forwards the content to l. If mandatory is
+// set to false, an empty configuration will be returned when filename does not exist. Otherwise this is is
+// reported as an error. The returned Loader implements FileLoader.
+func File(filename string, mandatory bool, l ReaderLoaderFunc) Loader {
+	return &fileLoader{
+		filename:  filename,
+		mandatory: mandatory,
+		l:         l,
+	}
+}
+
+type fileLoader struct {
+	filename  string
+	mandatory bool
+	l         ReaderLoaderFunc
+}
+
+func (l *fileLoader) Filename() string {
+	return l.filename
+}
+
+func (l *fileLoader) Load() (*Node, error) {
+	f, err := os.Open(l.filename)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) && !l.mandatory {
+			return NewNode(""), nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	n, err := l.l(f)
+	if err != nil {
+		var parseErr *ParseError
+		if errors.As(err, &parseErr) && parseErr.Source.File == "" {
+			parseErr.Source.File = l.filename
+			return nil, err
+		}
+		return nil, fmt.Errorf("%s: %w", l.filename, err)
+	}
+	n.annotate(Source{File: l.filename})
+	return n, nil
+}
+
 // --
 
-// JSON parses the r's content as JSON and converts it to a Node tree.
+// JSON parses the r's content as JSON and converts it to a Node tree. Numbers are decoded as json.Number, so
+// integers keep their full precision. The Nodes' sources carry the line and column of the values. Syntax errors
+// are reported as a *ParseError.
 func JSON(r io.Reader) (*Node, error) {
-	b, err := io.ReadAll(r)
+	data, err := io.ReadAll(r)
 	if err != nil {
 		return nil, err
 	}
-	var m map[string]interface{}
-	if err := json.Unmarshal(b, &m); err != nil {
+
+	d := jsonDecoder{
+		data: data,
+		dec:  json.NewDecoder(bytes.NewReader(data)),
+	}
+	d.dec.UseNumber()
+
+	n, err := d.decodeDocument()
+	if err != nil {
+		return nil, err
+	}
+	n.annotate(Source{Loader: "json"})
+	return n, nil
+}
+
+// jsonDecoder decodes a JSON document token by token to record the position of each value.
+type jsonDecoder struct {
+	data []byte
+	dec  *json.Decoder
+}
+
+// decodeDocument decodes the top-level JSON object.
+func (d *jsonDecoder) decodeDocument() (*Node, error) {
+	start := d.valueOffset()
+	tok, err := d.dec.Token()
+	if err != nil {
+		return nil, d.error(err, start)
+	}
+	if tok != json.Delim('{') {
+		return nil, d.error(fmt.Errorf("%w: top-level JSON value must be an object", ErrUnsupportedValue), start)
+	}
+
+	n, err := d.decodeValue(tok, start)
+	if err != nil {
 		return nil, err
 	}
-	return ConvertToNode(m)
+
+	end := d.valueOffset()
+	if _, err := d.dec.Token(); err != io.EOF {
+		return nil, d.error(fmt.Errorf("%w: unexpected data after top-level JSON value", ErrUnsupportedValue), end)
+	}
+
+	return n, nil
+}
+
+// decodeValue decodes the value starting with tok which has been read from offset.
+func (d *jsonDecoder) decodeValue(tok json.Token, offset int64) (*Node, error) {
+	var n *Node
+
+	switch tok {
+	case json.Delim('{'):
+		n = NewNode("")
+		for d.dec.More() {
+			keyOffset := d.valueOffset()
+			key, err := d.dec.Token()
+			if err != nil {
+				return nil, d.error(err, keyOffset)
+			}
+
+			c, err := d.decodeNext()
+			if err != nil {
+				return nil, err
+			}
+			n.set(ParseKeyPath(key.(string)), c)
+		}
+		if _, err := d.dec.Token(); err != nil {
+			return nil, d.error(err, d.valueOffset())
+		}
+
+	case json.Delim('['):
+		n = NewListNode()
+		for i := 0; d.dec.More(); i++ {
+			c, err := d.decodeNext()
+			if err != nil {
+				return nil, err
+			}
+			n.Children[indexKey(i)] = c
+		}
+		if _, err := d.dec.Token(); err != nil {
+			return nil, d.error(err, d.valueOffset())
+		}
+
+	default:
+		var err error
+		n, err = createNodeFromValue(tok)
+		if err != nil {
+			return nil, d.error(err, offset)
+		}
+	}
+
+	n.source.Line, n.source.Column = lineAndColumn(d.data, offset)
+	return n, nil
+}
+
+// decodeNext decodes the next value from the input.
+func (d *jsonDecoder) decodeNext() (*Node, error) {
+	offset := d.valueOffset()
+	tok, err := d.dec.Token()
+	if err != nil {
+		return nil, d.error(err, offset)
+	}
+	return d.decodeValue(tok, offset)
+}
+
+// valueOffset returns the offset of the next token by skipping whitespace and separators following the
+// decoder's current offset.
+func (d *jsonDecoder) valueOffset() int64 {
+	offset := d.dec.InputOffset()
+	for offset < int64(len(d.data)) {
+		switch d.data[offset] {
+		case ' ', '\t', '\r', '\n', ',', ':':
+			offset++
+		default:
+			return offset
+		}
+	}
+	return offset
+}
+
+// error wraps err, which occurred reading the token starting at offset, in a *ParseError. The position is
+// taken from err if it is a *json.SyntaxError pointing behind offset.
+func (d *jsonDecoder) error(err error, offset int64) error {
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) && syntaxErr.Offset-1 > offset {
+		// Offset counts the bytes read including the invalid one.
+		offset = syntaxErr.Offset - 1
+	}
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+
+	var src Source
+	src.Line, src.Column = lineAndColumn(d.data, offset)
+	return &ParseError{Source: src, Err: err}
+}
+
+// lineAndColumn returns the 1-based line and column of the byte at offset in data.
+func lineAndColumn(data []byte, offset int64) (int, int) {
+	if offset > int64(len(data)) {
+		offset = int64(len(data))
+	}
+	before := data[:offset]
+	line := bytes.Count(before, []byte("\n")) + 1
+	column := int(offset) - bytes.LastIndexByte(before, '\n')
+	return line, column
 }
 
 // JSONFile creates a Loader which loads JSON configuration from a file name.
@@ -77,17 +296,139 @@ func JSONFile(name string, mandatory bool) Loader {
 
 // --
 
-// YAML loades the content from r and converts it to a Node tree.
+// yamlUnsetTag is the YAML tag used to mark a key as unset (see Unset).
+const yamlUnsetTag = "!unset"
+
+// YAML loades the content from r and converts it to a Node tree. The Nodes' sources carry the line and
+// column of the values. A value tagged with !unset removes the key from the merged configuration (see
+// Unset).
 func YAML(r io.Reader) (*Node, error) {
 	b, err := io.ReadAll(r)
 	if err != nil {
 		return nil, err
 	}
-	var m map[string]interface{}
-	if err := yaml.Unmarshal(b, &m); err != nil {
-		return nil, err
+	var doc yaml.Node
+	if err := yaml.Unmarshal(b, &doc); err != nil {
+		return nil, yamlError(err)
+	}
+
+	n := NewNode("")
+	if len(doc.Content) > 0 {
+		n, err = createNodeFromYAML(doc.Content[0])
+		if err != nil {
+			return nil, err
+		}
+	}
+	n.annotate(Source{Loader: "yaml"})
+	return n, nil
+}
+
+// createNodeFromYAML converts the YAML node y to a Node tree.
+func createNodeFromYAML(y *yaml.Node) (*Node, error) {
+	var n *Node
+
+	switch {
+	case y.Tag == yamlUnsetTag:
+		n = NewUnsetNode()
+
+	case y.Kind == yaml.AliasNode:
+		return createNodeFromYAML(y.Alias)
+
+	case y.Kind == yaml.MappingNode:
+		n = NewNode("")
+		if err := mergeYAMLMapping(n, y); err != nil {
+			return nil, err
+		}
+
+	case y.Kind == yaml.SequenceNode:
+		n = NewListNode()
+		for i, c := range y.Content {
+			cn, err := createNodeFromYAML(c)
+			if err != nil {
+				return nil, err
+			}
+			n.Children[indexKey(i)] = cn
+		}
+
+	default:
+		var v interface{}
+		if err := y.Decode(&v); err != nil {
+			return nil, err
+		}
+		var err error
+		n, err = createNodeFromValue(v)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	n.source = Source{
+		Line:   y.Line,
+		Column: y.Column,
+	}
+	return n, nil
+}
+
+// mergeYAMLMapping merges the key-value-pairs of the YAML mapping y into n. Merge keys (<<) are resolved with
+// explicitly given keys taking precedence.
+func mergeYAMLMapping(n *Node, y *yaml.Node) error {
+	if y.Kind == yaml.AliasNode {
+		return mergeYAMLMapping(n, y.Alias)
+	}
+
+	if y.Kind == yaml.SequenceNode {
+		for _, c := range y.Content {
+			if err := mergeYAMLMapping(n, c); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if y.Kind != yaml.MappingNode {
+		return &ParseError{
+			Source: Source{Line: y.Line, Column: y.Column},
+			Err:    fmt.Errorf("%w: cannot merge %s", ErrUnsupportedValue, y.Tag),
+		}
+	}
+
+	for i := 0; i+1 < len(y.Content); i += 2 {
+		k, v := y.Content[i], y.Content[i+1]
+
+		if k.Tag == "!!merge" {
+			merged := NewNode("")
+			if err := mergeYAMLMapping(merged, v); err != nil {
+				return err
+			}
+			merged.OverwriteWith(n)
+			*n = *merged
+			continue
+		}
+
+		c, err := createNodeFromYAML(v)
+		if err != nil {
+			return err
+		}
+		n.set(ParseKeyPath(k.Value), c)
+	}
+
+	return nil
+}
+
+// yamlErrorPattern matches the messages of syntax errors reported by the yaml package.
+var yamlErrorPattern = regexp.MustCompile(`^yaml: line (\d+): (.*)$`)
+
+// yamlError converts err reported by the yaml package to a *ParseError, if err contains a line number.
+func yamlError(err error) error {
+	m := yamlErrorPattern.FindStringSubmatch(err.Error())
+	if m == nil {
+		return err
+	}
+	line, _ := strconv.Atoi(m[1])
+	return &ParseError{
+		Source: Source{Line: line},
+		Err:    errors.New("yaml: " + m[2]),
 	}
-	return ConvertToNode(m)
 }
 
 // YAMLFile creates a Loader which loads YAML configuration from a file name.
@@ -97,7 +438,8 @@ func YAMLFile(name string, mandatory bool) Loader {
 
 // --
 
-// TOML loads the content from r and converts it to a Node tree.
+// TOML loads the content from r and converts it to a Node tree. The Nodes' sources carry the line and column
+// of the values defined by key/value pairs and table headers. Syntax errors are reported as a *ParseError.
 func TOML(r io.Reader) (*Node, error) {
 	b, err := io.ReadAll(r)
 	if err != nil {
@@ -105,9 +447,39 @@ func TOML(r io.Reader) (*Node, error) {
 	}
 	var m map[string]interface{}
 	if err := toml.Unmarshal(b, &m); err != nil {
+		return nil, tomlError(b, err)
+	}
+	n, err := ConvertToNode(m)
+	if err != nil {
 		return nil, err
 	}
-	return ConvertToNode(m)
+	for _, p := range scanTOMLPositions(b) {
+		if c := n.resolve(p.path); c != nil {
+			c.source.Line, c.source.Column = p.line, p.column
+		}
+	}
+	n.annotate(Source{Loader: "toml"})
+	return n, nil
+}
+
+// tomlLinePattern matches the line prefix of the messages of errors reported by the toml package.
+var tomlLinePattern = regexp.MustCompile(`^toml: line \d+( \(last key "[^"]*"\))?: `)
+
+// tomlError converts err reported by the toml package while parsing data to a *ParseError.
+func tomlError(data []byte, err error) error {
+	var parseErr toml.ParseError
+	if !errors.As(err, &parseErr) {
+		return err
+	}
+
+	msg := parseErr.Message
+	if msg == "" {
+		msg = tomlLinePattern.ReplaceAllString(parseErr.Error(), "")
+	}
+
+	var src Source
+	src.Line, src.Column = lineAndColumn(data, int64(parseErr.Position.Start))
+	return &ParseError{Source: src, Err: errors.New("toml: " + msg)}
 }
 
 // TOMLFile creates a Loader which loads TOML configuration from a file name.
@@ -118,7 +490,8 @@ func TOMLFile(name string, mandatory bool) Loader {
 // --
 
 // Env creates a Loader which reads configuration values from the environment. Only env variables with a
-// name starting with prefix are considered. Use the empty string to select all variables.
+// name starting with prefix are considered. Use the empty string to select all variables. The values are
+// taken literally, i.e. references such as ${key} contained in them are not resolved.
 func Env(prefix string) Loader {
 	if len(prefix) > 0 && !strings.HasSuffix(prefix, "_") {
 		prefix += "_"
@@ -136,7 +509,14 @@ func Env(prefix string) Loader {
 			envMap[envKeyToMapKey(keyVal[0], prefix)] = keyVal[1]
 		}
 
-		return ConvertToNode(envMap)
+		n, err := ConvertToNode(envMap)
+		if err != nil {
+			return nil, err
+		}
+		n.annotate(Source{Loader: "env"})
+		// The shell has already expanded the variables' values.
+		n.markLiteral()
+		return n, nil
 	})
 }
 
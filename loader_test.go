@@ -1,9 +1,14 @@
 package appconf
 
 import (
+	"errors"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/go-test/deep"
+	"github.com/halimath/assertthat-go/assert"
+	"github.com/halimath/assertthat-go/is"
 )
 
 func TestStatic(t *testing.T) {
@@ -78,3 +83,189 @@ func assertLoader(t *testing.T, l Loader) {
 		t.Error(diff)
 	}
 }
+
+func TestYAML_mergeKeys(t *testing.T) {
+	got, err := YAML(strings.NewReader(`
+defaults: &defaults
+  host: localhost
+  port: 8080
+alpha:
+  <<: *defaults
+  host: alpha
+beta:
+  host: beta
+  <<: *defaults
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := &Node{
+		Children: map[Key]*Node{
+			"defaults": {Children: map[Key]*Node{"host": NewNode("localhost"), "port": NewNode("8080")}},
+			"alpha":    {Children: map[Key]*Node{"host": NewNode("alpha"), "port": NewNode("8080")}},
+			"beta":     {Children: map[Key]*Node{"host": NewNode("beta"), "port": NewNode("8080")}},
+		},
+	}
+
+	if diff := deep.Equal(want, got); diff != nil {
+		t.Error(diff)
+	}
+	if got.Children["beta"].Children["host"].Source().Line != 9 {
+		t.Errorf("unexpected source: %s", got.Children["beta"].Children["host"].Source())
+	}
+}
+
+func TestYAML_typedValues(t *testing.T) {
+	got, err := YAML(strings.NewReader(`
+port: 8080
+ratio: 1.0e+6
+enabled: yes
+debug: true
+created: 2022-04-01
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := &AppConfig{n: got}
+
+	assert.That(t, got.Children["port"].Kind(), is.Equal(KindInt))
+	assert.That(t, got.Children["ratio"].Kind(), is.Equal(KindFloat))
+	assert.That(t, c.GetInt("ratio"), is.Equal(1000000))
+	assert.That(t, got.Children["enabled"].Kind(), is.Equal(KindString))
+	assert.That(t, got.Children["debug"].Kind(), is.Equal(KindBool))
+	assert.That(t, got.Children["created"].Kind(), is.Equal(KindTime))
+}
+
+func TestTOML_typedValues(t *testing.T) {
+	got, err := TOML(strings.NewReader(`
+port = 8080
+ratio = 1e6
+day = 2022-04-01
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.That(t, got.Children["port"].Interface(), is.DeepEqual[interface{}](int64(8080)))
+	assert.That(t, got.Children["ratio"].Value, is.Equal("1000000"))
+	assert.That(t, got.Children["day"].Kind(), is.Equal(KindTime))
+	assert.That(t, got.Children["day"].Value, is.Equal("2022-04-01"))
+}
+
+func TestYAML_nullAndUnset(t *testing.T) {
+	c, err := New(
+		Static(map[string]interface{}{
+			"db.host":   "localhost",
+			"db.port":   5432,
+			"log.level": "info",
+		}),
+		LoaderFunc(func() (*Node, error) {
+			return YAML(strings.NewReader(`
+db:
+  host: ~
+  port: !unset
+log: !unset
+`))
+		}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.That(t, c.HasKey("db.host"), is.Equal(true))
+	assert.That(t, c.GetString("db.host"), is.Equal(""))
+	assert.That(t, c.HasKey("db.port"), is.Equal(false))
+	assert.That(t, c.HasKey("log"), is.Equal(false))
+}
+
+func TestJSON_positions(t *testing.T) {
+	got, err := JSON(strings.NewReader(`{
+  "db": {
+    "host": "localhost",
+    "port": 5432
+  },
+  "servers": [
+    {"host": "a"},
+    "b"
+  ]
+}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.That(t, got.resolve(ParseKeyPath("db")).Source(), is.Equal(Source{Loader: "json", Line: 2, Column: 9}))
+	assert.That(t, got.resolve(ParseKeyPath("db.host")).Source(), is.Equal(Source{Loader: "json", Line: 3, Column: 13}))
+	assert.That(t, got.resolve(ParseKeyPath("db.port")).Source(), is.Equal(Source{Loader: "json", Line: 4, Column: 13}))
+	assert.That(t, got.resolve(ParseKeyPath("servers.0.host")).Source(), is.Equal(Source{Loader: "json", Line: 7, Column: 14}))
+	assert.That(t, got.resolve(ParseKeyPath("servers.1")).Source(), is.Equal(Source{Loader: "json", Line: 8, Column: 5}))
+}
+
+func TestTOML_positions(t *testing.T) {
+	got, err := TOML(strings.NewReader(`title = "test"
+description = """
+multi-line [
+"""
+
+[db]
+host = "localhost"
+  "port" = 5432
+pool.size = 10
+
+[[servers]]
+host = "a"
+ports = [
+  80,
+  443,
+]
+
+[[servers]]
+host = "b"
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for key, want := range map[string]Source{
+		"title":           {Loader: "toml", Line: 1, Column: 9},
+		"description":     {Loader: "toml", Line: 2, Column: 15},
+		"db":              {Loader: "toml", Line: 6, Column: 1},
+		"db.host":         {Loader: "toml", Line: 7, Column: 8},
+		"db.port":         {Loader: "toml", Line: 8, Column: 12},
+		"db.pool.size":    {Loader: "toml", Line: 9, Column: 13},
+		"servers.0":       {Loader: "toml", Line: 11, Column: 1},
+		"servers.0.host":  {Loader: "toml", Line: 12, Column: 8},
+		"servers.0.ports": {Loader: "toml", Line: 13, Column: 9},
+		"servers.1.host":  {Loader: "toml", Line: 19, Column: 8},
+	} {
+		assert.That(t, got.resolve(ParseKeyPath(key)).Source(), is.Equal(want))
+	}
+}
+
+func TestFile_parseErrors(t *testing.T) {
+	dir := t.TempDir()
+
+	tests := map[string]struct {
+		loader  func(string, bool) Loader
+		content string
+		want    string
+	}{
+		"config.json": {JSONFile, "{\n  \"a\": 1,\n  \"b\": x\n}", "config.json:3:8: invalid character 'x' looking for beginning of value"},
+		"config.yaml": {YAMLFile, "a: 1\nb: [\n", "config.yaml:2: yaml: did not find expected node content"},
+		"config.toml": {TOMLFile, "a = 1\nb = = 2\n", "config.toml:2:5: toml: expected value but found '=' instead"},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			filename := filepath.Join(dir, name)
+			writeFile(t, filename, test.content)
+
+			_, err := test.loader(filename, true).Load()
+
+			var parseErr *ParseError
+			assert.That(t, errors.As(err, &parseErr), is.Equal(true))
+			assert.That(t, err.Error(), is.Equal(filepath.Join(dir, test.want)))
+		})
+	}
+}
@@ -0,0 +1,213 @@
+package appconf
+
+import (
+	"strconv"
+)
+
+// wildcardKey is the result of normalizing the key path element *.
+const wildcardKey Key = ""
+
+type listMergeMode int
+
+const (
+	listReplace listMergeMode = iota
+	listAppend
+	listMergeByIndex
+	listMergeByField
+)
+
+// ListMergeStrategy defines how a list defined by a loader is merged with a list defined for the same key by
+// a less significant loader.
+type ListMergeStrategy struct {
+	mode  listMergeMode
+	field Key
+}
+
+var (
+	// ListReplace replaces the less significant list. This is the default strategy.
+	ListReplace = ListMergeStrategy{mode: listReplace}
+
+	// ListAppend appends the items of the more significant list to the less significant one.
+	ListAppend = ListMergeStrategy{mode: listAppend}
+
+	// ListMergeByIndex merges items with the same index. Additional items are appended.
+	ListMergeByIndex = ListMergeStrategy{mode: listMergeByIndex}
+)
+
+// ListMergeByField merges items that contain the same value for the nested key field, such as a name. Items
+// without a matching counterpart are appended.
+func ListMergeByField(field string) ListMergeStrategy {
+	return ListMergeStrategy{
+		mode:  listMergeByField,
+		field: NormalizeKey(field),
+	}
+}
+
+// WithListMergeStrategy creates an Option that merges the lists stored under key using s. A key path element
+// of * matches every key, i.e. servers.*.tags selects the tags of all servers.
+func WithListMergeStrategy(key string, s ListMergeStrategy) Option {
+	return func(c *AppConfig) {
+		c.listMergeStrategies = append(c.listMergeStrategies, listMergeRule{
+			path:     ParseKeyPath(key),
+			strategy: s,
+		})
+	}
+}
+
+// listMergeRule associates a ListMergeStrategy with a key path pattern.
+type listMergeRule struct {
+	path     KeyPath
+	strategy ListMergeStrategy
+}
+
+func (r listMergeRule) matches(path KeyPath) bool {
+	if len(r.path) != len(path) {
+		return false
+	}
+	for i, k := range r.path {
+		if k != wildcardKey && k != path[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// listMergeStrategyFor returns the strategy to use for the list stored under path. The last matching rule
+// wins.
+func listMergeStrategyFor(rules []listMergeRule, path KeyPath) ListMergeStrategy {
+	for i := len(rules) - 1; i >= 0; i-- {
+		if rules[i].matches(path) {
+			return rules[i].strategy
+		}
+	}
+	return ListReplace
+}
+
+// overwriteWith merges o into n. path is the key path of n and rules define the strategies used to merge
+// lists.
+func (n *Node) overwriteWith(o *Node, path KeyPath, rules []listMergeRule) {
+	switch o.kind {
+	case KindList:
+		n.mergeList(o, path, rules)
+		return
+	case KindNull:
+		n.replaceWith(o)
+		return
+	}
+
+	n.Value = o.Value
+	n.source = o.source
+	// A value once marked as secret stays secret, even if it is overwritten by an unmarked value.
+	n.secret = n.secret || o.secret
+	n.literal = o.literal
+	if len(n.Children) == 0 {
+		n.kind = o.kind
+		n.raw = o.raw
+	}
+
+	if len(o.Children) == 0 {
+		return
+	}
+
+	if n.Children == nil {
+		n.Children = make(map[Key]*Node)
+	}
+	for key, node := range o.Children {
+		if node.kind == KindUnset {
+			delete(n.Children, key)
+			continue
+		}
+
+		found, ok := n.Children[key]
+		if !ok {
+			found = NewNode("")
+			n.Children[key] = found
+		}
+		found.overwriteWith(node, append(path[:len(path):len(path)], key), rules)
+	}
+
+	if n.kind == KindList && !hasListKeys(n.Children) {
+		n.kind = KindString
+	}
+}
+
+// mergeList merges the list o into n.
+func (n *Node) mergeList(o *Node, path KeyPath, rules []listMergeRule) {
+	if n.kind != KindList {
+		n.replaceWith(o)
+		return
+	}
+
+	items := n.Items()
+
+	switch s := listMergeStrategyFor(rules, path); s.mode {
+	case listAppend:
+		for _, item := range o.Items() {
+			items = append(items, item.Clone())
+		}
+
+	case listMergeByIndex:
+		for i, item := range o.Items() {
+			if i < len(items) {
+				items[i].overwriteWith(item, append(path[:len(path):len(path)], indexKey(i)), rules)
+			} else {
+				items = append(items, item.Clone())
+			}
+		}
+
+	case listMergeByField:
+		for _, item := range o.Items() {
+			i := indexOfItem(items, s.field, item)
+			if i < 0 {
+				items = append(items, item.Clone())
+			} else {
+				items[i].overwriteWith(item, append(path[:len(path):len(path)], indexKey(i)), rules)
+			}
+		}
+
+	default:
+		n.replaceWith(o)
+		return
+	}
+
+	n.source = o.source
+	n.Children = make(map[Key]*Node, len(items))
+	for i, item := range items {
+		n.Children[indexKey(i)] = item
+	}
+}
+
+// replaceWith replaces n's value and children with copies of o's.
+func (n *Node) replaceWith(o *Node) {
+	*n = *o.Clone()
+}
+
+// indexOfItem returns the index of the first element of items containing the same scalar value under field
+// as item or -1, if no such element exists.
+func indexOfItem(items []*Node, field Key, item *Node) int {
+	v, ok := item.Children[field]
+	if !ok || len(v.Children) > 0 {
+		return -1
+	}
+
+	for i, candidate := range items {
+		if c, ok := candidate.Children[field]; ok && len(c.Children) == 0 && c.Value == v.Value {
+			return i
+		}
+	}
+	return -1
+}
+
+// hasListKeys reports whether the keys of children form a contiguous range of list indices starting at 0.
+func hasListKeys(children map[Key]*Node) bool {
+	for i := 0; i < len(children); i++ {
+		if _, ok := children[indexKey(i)]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func indexKey(i int) Key {
+	return Key(strconv.Itoa(i))
+}
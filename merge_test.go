@@ -0,0 +1,136 @@
+package appconf
+
+import (
+	"testing"
+
+	"github.com/halimath/assertthat-go/assert"
+	"github.com/halimath/assertthat-go/is"
+)
+
+var (
+	baseServers = Static(map[string]interface{}{
+		"servers": []interface{}{
+			map[string]interface{}{"name": "alpha", "port": 8080},
+			map[string]interface{}{"name": "beta", "port": 8081},
+			map[string]interface{}{"name": "gamma", "port": 8082},
+		},
+	})
+
+	overlayServers = Static(map[string]interface{}{
+		"servers": []interface{}{
+			map[string]interface{}{"name": "beta", "port": 9091},
+		},
+	})
+)
+
+func TestListMergeStrategies(t *testing.T) {
+	tests := map[string]struct {
+		opts []Option
+		want interface{}
+	}{
+		"default": {
+			want: []interface{}{
+				map[string]interface{}{"name": "beta", "port": int64(9091)},
+			},
+		},
+		"replace": {
+			opts: []Option{WithListMergeStrategy("servers", ListReplace)},
+			want: []interface{}{
+				map[string]interface{}{"name": "beta", "port": int64(9091)},
+			},
+		},
+		"append": {
+			opts: []Option{WithListMergeStrategy("servers", ListAppend)},
+			want: []interface{}{
+				map[string]interface{}{"name": "alpha", "port": int64(8080)},
+				map[string]interface{}{"name": "beta", "port": int64(8081)},
+				map[string]interface{}{"name": "gamma", "port": int64(8082)},
+				map[string]interface{}{"name": "beta", "port": int64(9091)},
+			},
+		},
+		"mergeByIndex": {
+			opts: []Option{WithListMergeStrategy("servers", ListMergeByIndex)},
+			want: []interface{}{
+				map[string]interface{}{"name": "beta", "port": int64(9091)},
+				map[string]interface{}{"name": "beta", "port": int64(8081)},
+				map[string]interface{}{"name": "gamma", "port": int64(8082)},
+			},
+		},
+		"mergeByField": {
+			opts: []Option{WithListMergeStrategy("servers", ListMergeByField("Name"))},
+			want: []interface{}{
+				map[string]interface{}{"name": "alpha", "port": int64(8080)},
+				map[string]interface{}{"name": "beta", "port": int64(9091)},
+				map[string]interface{}{"name": "gamma", "port": int64(8082)},
+			},
+		},
+		"otherKey": {
+			opts: []Option{WithListMergeStrategy("backends", ListAppend)},
+			want: []interface{}{
+				map[string]interface{}{"name": "beta", "port": int64(9091)},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			c, err := NewWithOptions([]Loader{baseServers, overlayServers}, test.opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got map[string]interface{}
+			if err := c.Bind(&got); err != nil {
+				t.Fatal(err)
+			}
+
+			assert.That(t, got["servers"], is.DeepEqual(test.want))
+		})
+	}
+}
+
+func TestListMergeStrategies_wildcard(t *testing.T) {
+	c, err := NewWithOptions([]Loader{
+		Static(map[string]interface{}{
+			"servers": []interface{}{
+				map[string]interface{}{"tags": []string{"a"}},
+			},
+		}),
+		Static(map[string]interface{}{
+			"servers": []interface{}{
+				map[string]interface{}{"tags": []string{"b"}},
+			},
+		}),
+	},
+		WithListMergeStrategy("servers", ListMergeByIndex),
+		WithListMergeStrategy("servers.*.tags", ListAppend),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.That(t, c.GetString("servers.0.tags.0"), is.Equal("a"))
+	assert.That(t, c.GetString("servers.0.tags.1"), is.Equal("b"))
+}
+
+func TestNodeOverwriteWith_listWithIndexedMap(t *testing.T) {
+	n := NewNode("")
+	n.OverwriteWith(NewListNode(NewNode("a"), NewNode("b")))
+	n.OverwriteWith(&Node{
+		Children: map[Key]*Node{
+			"1": NewNode("c"),
+		},
+	})
+
+	assert.That(t, n.Kind(), is.Equal(KindList))
+	assert.That(t, len(n.Items()), is.Equal(2))
+	assert.That(t, n.Items()[1].Value, is.Equal("c"))
+
+	n.OverwriteWith(&Node{
+		Children: map[Key]*Node{
+			"foo": NewNode("bar"),
+		},
+	})
+
+	assert.That(t, n.Kind(), is.Equal(KindMap))
+}
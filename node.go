@@ -1,10 +1,15 @@
 package appconf
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
+	"net"
+	"net/url"
 	"reflect"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -18,6 +23,8 @@ var (
 	ErrUnsupportedValue = errors.New("unsupported value")
 	ErrNoSuchKey        = errors.New("no such key")
 	ErrNotAScalar       = errors.New("not a scalar value")
+	ErrNotAList         = errors.New("not a list")
+	ErrNotAMap          = errors.New("not a map")
 
 	keyFilterRegexp = regexp.MustCompile(`[^0-9a-z]`)
 )
@@ -51,9 +58,171 @@ func ParseKeyPath(s string) KeyPath {
 	return path
 }
 
+// Source describes the origin of a Node's value.
+type Source struct {
+	// Loader is the name of the loader that produced the value.
+	Loader string
+	// File is the name of the file the value has been read from. It is empty if the value has not been read
+	// from a file.
+	File string
+	// Line and Column describe the position of the value inside File. Both are 1-based and zero if the
+	// position is not known.
+	Line, Column int
+}
+
+// Position returns the position described by s in the form file:line:column. Unknown parts are omitted.
+func (s Source) Position() string {
+	if s.File == "" {
+		return ""
+	}
+	if s.Line == 0 {
+		return s.File
+	}
+	if s.Column == 0 {
+		return fmt.Sprintf("%s:%d", s.File, s.Line)
+	}
+	return fmt.Sprintf("%s:%d:%d", s.File, s.Line, s.Column)
+}
+
+// location describes the position of s for messages. Unlike Position it describes positions without a file
+// as line L, column C. It returns the empty string if the position is not known.
+func (s Source) location() string {
+	if p := s.Position(); p != "" {
+		return p
+	}
+
+	switch {
+	case s.Line > 0 && s.Column > 0:
+		return fmt.Sprintf("line %d, column %d", s.Line, s.Column)
+	case s.Line > 0:
+		return fmt.Sprintf("line %d", s.Line)
+	default:
+		return ""
+	}
+}
+
+func (s Source) String() string {
+	p := s.Position()
+	if p == "" {
+		return s.Loader
+	}
+	if s.Loader == "" {
+		return p
+	}
+	return fmt.Sprintf("%s (%s)", s.Loader, p)
+}
+
+// Kind describes the kind of value stored in a Node.
+type Kind int
+
+const (
+	// KindString denotes a string value. This is the kind of Nodes created with NewNode.
+	KindString Kind = iota
+	// KindInt denotes an integer value.
+	KindInt
+	// KindFloat denotes a floating point value.
+	KindFloat
+	// KindBool denotes a boolean value.
+	KindBool
+	// KindTime denotes a timestamp, date or time value.
+	KindTime
+	// KindMap denotes a Node with nested values.
+	KindMap
+	// KindList denotes a list of values. The items are stored as children using their index as key.
+	KindList
+	// KindNull denotes an explicit null value, such as a JSON null or a YAML ~.
+	KindNull
+	// KindUnset denotes the explicit removal of a key. Nodes of this kind are only returned from loaders;
+	// merging such a Node removes the key from the merged tree.
+	KindUnset
+)
+
+var (
+	// Unset can be used as a value passed to Static or ConvertToNode to remove a key defined by a less
+	// significant loader.
+	Unset = unsetMarker{}
+)
+
+// unsetMarker is the type of Unset.
+type unsetMarker struct{}
+
+func (k Kind) String() string {
+	switch k {
+	case KindString:
+		return "string"
+	case KindInt:
+		return "int"
+	case KindFloat:
+		return "float"
+	case KindBool:
+		return "bool"
+	case KindTime:
+		return "time"
+	case KindMap:
+		return "map"
+	case KindList:
+		return "list"
+	case KindNull:
+		return "null"
+	case KindUnset:
+		return "unset"
+	default:
+		return fmt.Sprintf("Kind(%d)", int(k))
+	}
+}
+
+// Node implements a single node of a configuration tree. Scalar values are stored with their original type as
+// reported by the loader. Value always contains the value's textual representation.
 type Node struct {
 	Value    string
 	Children map[Key]*Node
+
+	kind   Kind
+	raw    interface{}
+	source Source
+	// secret marks the value as secret; it has been created from a Secret.
+	secret bool
+	// literal exempts the value from interpolation, i.e. because it has been read from an environment
+	// variable.
+	literal bool
+}
+
+// NewNullNode creates a Node of kind KindNull.
+func NewNullNode() *Node {
+	n := NewNode("")
+	n.kind = KindNull
+	return n
+}
+
+// NewUnsetNode creates a Node of kind KindUnset which removes the key it is stored under when merged with a
+// less significant tree.
+func NewUnsetNode() *Node {
+	n := NewNode("")
+	n.kind = KindUnset
+	return n
+}
+
+// NewListNode creates a Node of kind KindList containing items.
+func NewListNode(items ...*Node) *Node {
+	n := NewNode("")
+	n.kind = KindList
+	for i, item := range items {
+		n.Children[indexKey(i)] = item
+	}
+	return n
+}
+
+// Items returns the items of a list Node in order. It returns nil if n is not a list.
+func (n *Node) Items() []*Node {
+	if n.kind != KindList {
+		return nil
+	}
+
+	items := make([]*Node, len(n.Children))
+	for i := range items {
+		items[i] = n.Children[indexKey(i)]
+	}
+	return items
 }
 
 func NewNode(v string) *Node {
@@ -63,6 +232,71 @@ func NewNode(v string) *Node {
 	}
 }
 
+// Kind returns the kind of value stored in n.
+func (n *Node) Kind() Kind {
+	if n.kind == KindString && len(n.Children) > 0 {
+		return KindMap
+	}
+	return n.kind
+}
+
+// Interface returns n's value using its original type. The returned value is either a string, an int64 (or an
+// uint64 for integers exceeding the range of int64), a float64, a bool or a time.Time. It returns nil for
+// null values, Nodes with nested values and lists.
+func (n *Node) Interface() interface{} {
+	switch n.Kind() {
+	case KindString:
+		return n.Value
+	case KindMap, KindList, KindNull, KindUnset:
+		return nil
+	default:
+		return n.raw
+	}
+}
+
+// Source returns the origin of n's value.
+func (n *Node) Source() Source {
+	return n.source
+}
+
+// SetSource sets s as the source of n and all of its descendants.
+func (n *Node) SetSource(s Source) {
+	n.source = s
+	for _, c := range n.Children {
+		c.SetSource(s)
+	}
+}
+
+// walk calls f for n and all of its descendants.
+func (n *Node) walk(f func(*Node)) {
+	f(n)
+	for _, c := range n.Children {
+		c.walk(f)
+	}
+}
+
+// annotate fills the fields of the sources of n and all of its descendants, which are not set yet, with the
+// values from s.
+func (n *Node) annotate(s Source) {
+	if n.source.Loader == "" {
+		n.source.Loader = s.Loader
+	}
+	if n.source.File == "" {
+		n.source.File = s.File
+	}
+	for _, c := range n.Children {
+		c.annotate(s)
+	}
+}
+
+// markLiteral exempts n and all its descendants from interpolation.
+func (n *Node) markLiteral() {
+	n.literal = true
+	for _, c := range n.Children {
+		c.markLiteral()
+	}
+}
+
 func (n *Node) resolve(path KeyPath) *Node {
 	if len(path) == 0 {
 		return n
@@ -75,30 +309,62 @@ func (n *Node) resolve(path KeyPath) *Node {
 	return v.resolve(path[1:])
 }
 
+// OverwriteWith merges o into n with values from o taking precedence. n is modified in place while o is left
+// untouched. Nodes from o are copied, so n and o do not share any Nodes afterwards. Lists from o replace lists
+// in n. Use WithListMergeStrategy to configure a different strategy for an AppConfig. Keys with a value of
+// kind KindUnset in o are removed from n. A null value from o replaces the value and all nested values in n.
 func (n *Node) OverwriteWith(o *Node) {
-	n.Value = o.Value
-	for key, node := range o.Children {
-		found, ok := n.Children[key]
-		if !ok {
-			n.Children[key] = node
-		} else {
-			found.OverwriteWith(node)
-		}
+	n.overwriteWith(o, nil, nil)
+}
+
+// Clone creates a deep copy of n.
+func (n *Node) Clone() *Node {
+	c := NewNode(n.Value)
+	c.kind = n.kind
+	c.raw = n.raw
+	c.source = n.source
+	c.secret = n.secret
+	c.literal = n.literal
+	for key, node := range n.Children {
+		c.Children[key] = node.Clone()
 	}
+	return c
 }
 
 func (n *Node) Dump(indent int) {
-	fmt.Printf("%v\n", n.Value)
+	if n.secret {
+		fmt.Println(redacted)
+	} else {
+		fmt.Printf("%v\n", n.Value)
+	}
 
 	indent += 2
 
-	for k, v := range n.Children {
+	for _, k := range n.sortedKeys() {
 		for i := 0; i < indent; i++ {
 			fmt.Print(" ")
 		}
 		fmt.Print(k, ": ")
-		v.Dump(indent)
+		n.Children[k].Dump(indent)
+	}
+}
+
+// sortedKeys returns the keys of n's children in a stable order. The items of a list are returned in index
+// order; all other keys are sorted alphabetically.
+func (n *Node) sortedKeys() []Key {
+	keys := make([]Key, 0, len(n.Children))
+	if n.kind == KindList {
+		for i := 0; i < len(n.Children); i++ {
+			keys = append(keys, indexKey(i))
+		}
+		return keys
+	}
+
+	for k := range n.Children {
+		keys = append(keys, k)
 	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+	return keys
 }
 
 func (n *Node) GetString() string {
@@ -111,6 +377,10 @@ func (n *Node) GetStringE() (string, error) {
 		return "", ErrNotAScalar
 	}
 
+	if n.kind == KindNull {
+		return "", nil
+	}
+
 	return n.Value, nil
 }
 
@@ -121,7 +391,13 @@ func (n *Node) GetInt() int {
 
 func (n *Node) GetIntE() (int, error) {
 	v, err := n.GetInt64E()
-	return int(v), err
+	if err != nil {
+		return 0, err
+	}
+	if int64(int(v)) != v {
+		return 0, n.numError("ParseInt", strconv.ErrRange)
+	}
+	return int(v), nil
 }
 
 func (n *Node) GetInt64() int64 {
@@ -130,12 +406,32 @@ func (n *Node) GetInt64() int64 {
 }
 
 func (n *Node) GetInt64E() (int64, error) {
-	v, err := n.GetStringE()
-	if err != nil {
+	if err := n.checkScalar(); err != nil {
 		return 0, err
 	}
 
-	return strconv.ParseInt(v, 10, 64)
+	if n.kind == KindNull {
+		return 0, nil
+	}
+
+	switch n.kind {
+	case KindInt:
+		if v, ok := n.raw.(int64); ok {
+			return v, nil
+		}
+		return 0, n.numError("ParseInt", strconv.ErrRange)
+	case KindFloat:
+		f := n.raw.(float64)
+		if f != math.Trunc(f) {
+			return 0, n.numError("ParseInt", strconv.ErrSyntax)
+		}
+		if f < math.MinInt64 || f >= math.MaxInt64 {
+			return 0, n.numError("ParseInt", strconv.ErrRange)
+		}
+		return int64(f), nil
+	default:
+		return strconv.ParseInt(n.Value, 10, 64)
+	}
 }
 
 func (n *Node) GetUint() uint {
@@ -145,7 +441,13 @@ func (n *Node) GetUint() uint {
 
 func (n *Node) GetUintE() (uint, error) {
 	v, err := n.GetUint64E()
-	return uint(v), err
+	if err != nil {
+		return 0, err
+	}
+	if uint64(uint(v)) != v {
+		return 0, n.numError("ParseUint", strconv.ErrRange)
+	}
+	return uint(v), nil
 }
 
 func (n *Node) GetUint64() uint64 {
@@ -154,12 +456,37 @@ func (n *Node) GetUint64() uint64 {
 }
 
 func (n *Node) GetUint64E() (uint64, error) {
-	v, err := n.GetStringE()
-	if err != nil {
+	if err := n.checkScalar(); err != nil {
 		return 0, err
 	}
 
-	return strconv.ParseUint(v, 10, 64)
+	if n.kind == KindNull {
+		return 0, nil
+	}
+
+	switch n.kind {
+	case KindInt:
+		switch v := n.raw.(type) {
+		case uint64:
+			return v, nil
+		case int64:
+			if v < 0 {
+				return 0, n.numError("ParseUint", strconv.ErrSyntax)
+			}
+			return uint64(v), nil
+		}
+	case KindFloat:
+		f := n.raw.(float64)
+		if f != math.Trunc(f) || f < 0 {
+			return 0, n.numError("ParseUint", strconv.ErrSyntax)
+		}
+		if f >= math.MaxUint64 {
+			return 0, n.numError("ParseUint", strconv.ErrRange)
+		}
+		return uint64(f), nil
+	}
+
+	return strconv.ParseUint(n.Value, 10, 64)
 }
 
 func (n *Node) GetFloat32() float32 {
@@ -168,13 +495,15 @@ func (n *Node) GetFloat32() float32 {
 }
 
 func (n *Node) GetFloat32E() (float32, error) {
-	v, err := n.GetStringE()
+	f, err := n.GetFloat64E()
 	if err != nil {
 		return 0, err
 	}
 
-	f, err := strconv.ParseFloat(v, 32)
-	return float32(f), err
+	if math.Abs(f) > math.MaxFloat32 {
+		return 0, n.numError("ParseFloat", strconv.ErrRange)
+	}
+	return float32(f), nil
 }
 
 func (n *Node) GetFloat64() float64 {
@@ -183,12 +512,27 @@ func (n *Node) GetFloat64() float64 {
 }
 
 func (n *Node) GetFloat64E() (float64, error) {
-	v, err := n.GetStringE()
-	if err != nil {
+	if err := n.checkScalar(); err != nil {
 		return 0, err
 	}
 
-	return strconv.ParseFloat(v, 64)
+	if n.kind == KindNull {
+		return 0, nil
+	}
+
+	switch n.kind {
+	case KindInt:
+		switch v := n.raw.(type) {
+		case int64:
+			return float64(v), nil
+		case uint64:
+			return float64(v), nil
+		}
+	case KindFloat:
+		return n.raw.(float64), nil
+	}
+
+	return strconv.ParseFloat(n.Value, 64)
 }
 
 func (n *Node) GetComplex128() complex128 {
@@ -197,12 +541,20 @@ func (n *Node) GetComplex128() complex128 {
 }
 
 func (n *Node) GetComplex128E() (complex128, error) {
-	v, err := n.GetStringE()
-	if err != nil {
+	if err := n.checkScalar(); err != nil {
 		return 0, err
 	}
 
-	return strconv.ParseComplex(v, 128)
+	if n.kind == KindNull {
+		return 0, nil
+	}
+
+	if n.kind == KindInt || n.kind == KindFloat {
+		f, err := n.GetFloat64E()
+		return complex(f, 0), err
+	}
+
+	return strconv.ParseComplex(n.Value, 128)
 }
 
 func (n *Node) GetBool() bool {
@@ -211,12 +563,19 @@ func (n *Node) GetBool() bool {
 }
 
 func (n *Node) GetBoolE() (bool, error) {
-	v, err := n.GetStringE()
-	if err != nil {
+	if err := n.checkScalar(); err != nil {
 		return false, err
 	}
 
-	return strconv.ParseBool(v)
+	if n.kind == KindNull {
+		return false, nil
+	}
+
+	if n.kind == KindBool {
+		return n.raw.(bool), nil
+	}
+
+	return strconv.ParseBool(n.Value)
 }
 
 func (n *Node) GetDuration() time.Duration {
@@ -226,94 +585,357 @@ func (n *Node) GetDuration() time.Duration {
 
 func (n *Node) GetDurationE() (time.Duration, error) {
 	v, err := n.GetStringE()
-	if err != nil {
+	if err != nil || n.kind == KindNull {
 		return 0, err
 	}
 
 	return time.ParseDuration(v)
 }
 
-func ConvertToNode(m map[string]interface{}) (*Node, error) {
-	n := NewNode("")
+// timeLayouts contains the layouts accepted by GetTimeE.
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02",
+}
 
-	for k, val := range m {
-		path := ParseKeyPath(k)
+func (n *Node) GetTime() time.Time {
+	t, _ := n.GetTimeE()
+	return t
+}
 
-		var key Key
-		var valueNode *Node
-		var err error
+// GetTimeE returns n's value as a time.Time. Timestamps and dates loaded as such (i.e. from TOML or YAML) are
+// returned as is. Strings are parsed as RFC 3339 timestamps or dates (2006-01-02); values without a time zone
+// are interpreted as UTC.
+func (n *Node) GetTimeE() (time.Time, error) {
+	v, err := n.GetStringE()
+	if err != nil || n.kind == KindNull {
+		return time.Time{}, err
+	}
 
-		if len(path) > 1 {
-			key = path[0]
-			valueNode, err = ConvertToNode(map[string]interface{}{
-				path[1:].Join(): val, // TODO: This is not very efficient!
-			})
-		} else {
-			valueNode, err = createNodeFromValue(val)
-			key = path[0]
+	if n.kind == KindTime {
+		return n.raw.(time.Time), nil
+	}
+
+	for _, layout := range timeLayouts {
+		if t, err := time.Parse(layout, v); err == nil {
+			return t, nil
 		}
+	}
+
+	return time.Time{}, fmt.Errorf("invalid time: %q", v)
+}
+
+func (n *Node) GetURL() *url.URL {
+	u, _ := n.GetURLE()
+	return u
+}
+
+// GetURLE returns n's value parsed as an URL. It returns nil for null values.
+func (n *Node) GetURLE() (*url.URL, error) {
+	v, err := n.GetStringE()
+	if err != nil || n.kind == KindNull {
+		return nil, err
+	}
+
+	return url.Parse(v)
+}
+
+func (n *Node) GetIP() net.IP {
+	ip, _ := n.GetIPE()
+	return ip
+}
+
+// GetIPE returns n's value parsed as an IPv4 or IPv6 address. It returns nil for null values.
+func (n *Node) GetIPE() (net.IP, error) {
+	v, err := n.GetStringE()
+	if err != nil || n.kind == KindNull {
+		return nil, err
+	}
+
+	ip := net.ParseIP(v)
+	if ip == nil {
+		return nil, &net.ParseError{Type: "IP address", Text: v}
+	}
+	return ip, nil
+}
+
+func (n *Node) GetIPNet() *net.IPNet {
+	ipNet, _ := n.GetIPNetE()
+	return ipNet
+}
+
+// GetIPNetE returns n's value parsed as a network in CIDR notation, i.e. 192.168.0.0/16. It returns nil for
+// null values.
+func (n *Node) GetIPNetE() (*net.IPNet, error) {
+	v, err := n.GetStringE()
+	if err != nil || n.kind == KindNull {
+		return nil, err
+	}
+
+	_, ipNet, err := net.ParseCIDR(v)
+	return ipNet, err
+}
+
+func (n *Node) GetByteSize() ByteSize {
+	s, _ := n.GetByteSizeE()
+	return s
+}
+
+// GetByteSizeE returns n's value as a ByteSize. Numbers are taken as bytes; strings are parsed using
+// ParseByteSize.
+func (n *Node) GetByteSizeE() (ByteSize, error) {
+	if n.kind == KindInt || n.kind == KindFloat {
+		u, err := n.GetUint64E()
+		return ByteSize(u), err
+	}
+
+	v, err := n.GetStringE()
+	if err != nil || n.kind == KindNull {
+		return 0, err
+	}
+
+	return ParseByteSize(v)
+}
+
+func (n *Node) GetRegexp() *regexp.Regexp {
+	re, _ := n.GetRegexpE()
+	return re
+}
+
+// GetRegexpE returns n's value compiled as a regular expression. It returns nil for null values.
+func (n *Node) GetRegexpE() (*regexp.Regexp, error) {
+	v, err := n.GetStringE()
+	if err != nil || n.kind == KindNull {
+		return nil, err
+	}
 
+	return regexp.Compile(v)
+}
+
+// checkScalar returns an error if n contains nested values.
+func (n *Node) checkScalar() error {
+	if len(n.Children) != 0 {
+		return ErrNotAScalar
+	}
+	return nil
+}
+
+// numError creates an error describing the failed conversion of n's value to a number. The error uses the
+// same format as the errors returned from the strconv package.
+func (n *Node) numError(fn string, err error) error {
+	return &strconv.NumError{Func: fn, Num: n.Value, Err: err}
+}
+
+func ConvertToNode(m map[string]interface{}) (*Node, error) {
+	n := NewNode("")
+
+	for k, val := range m {
+		valueNode, err := createNodeFromValue(val)
 		if err != nil {
 			return nil, err
 		}
 
-		existing, ok := n.Children[key]
+		n.set(ParseKeyPath(k), valueNode)
+	}
+
+	return n, nil
+}
+
+// put stores v as the descendant of n identified by path replacing any existing node. Intermediate nodes are
+// created as needed.
+func (n *Node) put(path KeyPath, v *Node) {
+	for _, key := range path[:len(path)-1] {
+		c, ok := n.Children[key]
+		if !ok || (c.Kind() != KindMap && c.Kind() != KindList) {
+			c = NewNode("")
+			c.source = v.source
+			n.Children[key] = c
+		}
+		n = c
+	}
+	n.Children[path[len(path)-1]] = v
+}
+
+// set stores v as the descendant of n identified by path. Intermediate nodes are created as needed. If a node
+// already exists at path, v is merged into the existing node.
+func (n *Node) set(path KeyPath, v *Node) {
+	key := path[0]
+	existing, ok := n.Children[key]
+
+	if len(path) == 1 {
 		if ok {
-			existing.OverwriteWith(valueNode)
+			existing.OverwriteWith(v)
 		} else {
-			n.Children[key] = valueNode
+			n.Children[key] = v
 		}
+		return
 	}
 
-	return n, nil
+	if !ok {
+		existing = NewNode("")
+		n.Children[key] = existing
+	}
+	existing.set(path[1:], v)
 }
 
 func createNodeFromValue(val interface{}) (*Node, error) {
-	t := reflect.TypeOf(val)
-	switch t.Kind() {
-	case reflect.Slice:
-		s, ok := val.([]interface{})
-		if !ok {
-			return nil, fmt.Errorf("%w: nested slice invalid: %v", ErrUnsupportedValue, val)
+	switch v := val.(type) {
+	case nil:
+		return NewNullNode(), nil
+	case unsetMarker:
+		return NewUnsetNode(), nil
+	case Secret:
+		n := NewNode(string(v))
+		n.secret = true
+		return n, nil
+	case time.Duration:
+		return NewNode(v.String()), nil
+	case time.Time:
+		return newTimeNode(v), nil
+	case json.Number:
+		return newNumberNode(v)
+	case net.IP:
+		return NewNode(v.String()), nil
+	case *net.IPNet, *url.URL, *regexp.Regexp:
+		if rv := reflect.ValueOf(v); rv.IsNil() {
+			return NewNullNode(), nil
 		}
-		return createNodeFromSlice(s)
+		return NewNode(v.(fmt.Stringer).String()), nil
+	case ByteSize:
+		return NewNode(v.String()), nil
+	}
+
+	rv := reflect.ValueOf(val)
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		return createNodeFromSlice(rv)
 	case reflect.Map:
-		nested, ok := val.(map[string]interface{})
-		if !ok {
-			return nil, fmt.Errorf("%w: nested map is not a ConfigMap: %v", ErrUnsupportedValue, val)
+		if nested, ok := val.(map[string]interface{}); ok {
+			return ConvertToNode(nested)
 		}
-		return ConvertToNode(nested)
-	case reflect.Bool,
-		reflect.Int,
+		return createNodeFromMap(rv)
+	case reflect.Bool:
+		return newScalarNode(KindBool, rv.Bool(), strconv.FormatBool(rv.Bool())), nil
+	case reflect.Int,
 		reflect.Int8,
 		reflect.Int16,
 		reflect.Int32,
-		reflect.Int64,
-		reflect.Uint,
+		reflect.Int64:
+		return newScalarNode(KindInt, rv.Int(), strconv.FormatInt(rv.Int(), 10)), nil
+	case reflect.Uint,
 		reflect.Uint8,
 		reflect.Uint16,
 		reflect.Uint32,
-		reflect.Uint64,
-		reflect.Complex64,
-		reflect.Complex128,
-		reflect.Float32,
-		reflect.Float64,
-		reflect.String:
+		reflect.Uint64:
+		u := rv.Uint()
+		if u <= math.MaxInt64 {
+			return newScalarNode(KindInt, int64(u), strconv.FormatUint(u, 10)), nil
+		}
+		return newScalarNode(KindInt, u, strconv.FormatUint(u, 10)), nil
+	case reflect.Float32:
+		// Format the float32 with its own precision and parse the result to prevent float64 digits
+		// introduced by widening the value.
+		s := formatFloat(rv.Float(), 32)
+		f, _ := strconv.ParseFloat(s, 64)
+		return newScalarNode(KindFloat, f, s), nil
+	case reflect.Float64:
+		return newScalarNode(KindFloat, rv.Float(), formatFloat(rv.Float(), 64)), nil
+	case reflect.Complex64,
+		reflect.Complex128:
 		return NewNode(fmt.Sprint(val)), nil
+	case reflect.String:
+		return NewNode(rv.String()), nil
 	default:
 		return nil, fmt.Errorf("%w: unsupported value type: %T", ErrUnsupportedValue, val)
 	}
 }
 
-func createNodeFromSlice(val []interface{}) (*Node, error) {
+func newScalarNode(k Kind, raw interface{}, s string) *Node {
+	n := NewNode(s)
+	n.kind = k
+	n.raw = raw
+	return n
+}
+
+// newNumberNode creates a Node from a number decoded from JSON. Integers are kept as integers with full
+// precision.
+func newNumberNode(v json.Number) (*Node, error) {
+	s := v.String()
+
+	if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return newScalarNode(KindInt, i, s), nil
+	}
+	if u, err := strconv.ParseUint(s, 10, 64); err == nil {
+		return newScalarNode(KindInt, u, s), nil
+	}
+
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid number: %s", ErrUnsupportedValue, s)
+	}
+	return newScalarNode(KindFloat, f, s), nil
+}
+
+// Names of the locations used by github.com/BurntSushi/toml for local date and time values.
+const (
+	tomlLocalDateTime = "datetime-local"
+	tomlLocalDate     = "date-local"
+	tomlLocalTime     = "time-local"
+)
+
+func newTimeNode(t time.Time) *Node {
+	var s string
+	switch t.Location().String() {
+	case tomlLocalDateTime:
+		s = t.Format("2006-01-02T15:04:05.999999999")
+	case tomlLocalDate:
+		s = t.Format("2006-01-02")
+	case tomlLocalTime:
+		s = t.Format("15:04:05.999999999")
+	default:
+		s = t.Format(time.RFC3339Nano)
+	}
+	return newScalarNode(KindTime, t, s)
+}
+
+// formatFloat formats f using the shortest representation that parses back to f. Unlike
+// strconv.FormatFloat(f, 'g', -1, bitSize), numbers of common magnitude are formatted without an exponent.
+func formatFloat(f float64, bitSize int) string {
+	if a := math.Abs(f); a == 0 || (a >= 1e-6 && a < 1e21) {
+		return strconv.FormatFloat(f, 'f', -1, bitSize)
+	}
+	return strconv.FormatFloat(f, 'g', -1, bitSize)
+}
+
+func createNodeFromSlice(rv reflect.Value) (*Node, error) {
+	r := NewListNode()
+
+	for idx := 0; idx < rv.Len(); idx++ {
+		n, err := createNodeFromValue(rv.Index(idx).Interface())
+		if err != nil {
+			return nil, err
+		}
+		r.Children[indexKey(idx)] = n
+	}
+
+	return r, nil
+}
+
+func createNodeFromMap(rv reflect.Value) (*Node, error) {
+	if rv.Type().Key().Kind() != reflect.String {
+		return nil, fmt.Errorf("%w: nested map must have string keys: %v", ErrUnsupportedValue, rv.Interface())
+	}
+
 	r := NewNode("")
 
-	for idx, v := range val {
-		n, err := createNodeFromValue(v)
+	iter := rv.MapRange()
+	for iter.Next() {
+		n, err := createNodeFromValue(iter.Value().Interface())
 		if err != nil {
 			return nil, err
 		}
-		r.Children[Key(strconv.Itoa(idx))] = n
+		r.set(ParseKeyPath(iter.Key().String()), n)
 	}
 
 	return r, nil
@@ -1,6 +1,9 @@
 package appconf
 
 import (
+	"errors"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
@@ -84,3 +87,169 @@ func TestConvertToNode(t *testing.T) {
 
 	assert.That(t, got, is.DeepEqual(want))
 }
+
+func TestNodeOverwriteWith_doesNotShareNodes(t *testing.T) {
+	n := NewNode("")
+	o := &Node{
+		Children: map[Key]*Node{
+			"spam": {
+				Children: map[Key]*Node{
+					"eggs": NewNode("ham"),
+				},
+			},
+		},
+	}
+
+	n.OverwriteWith(o)
+	n.OverwriteWith(&Node{
+		Children: map[Key]*Node{
+			"spam": {
+				Children: map[Key]*Node{
+					"eggs": NewNode("bacon"),
+				},
+			},
+		},
+	})
+
+	assert.That(t, o.Children["spam"].Children["eggs"].Value, is.Equal("ham"))
+	assert.That(t, n.Children["spam"].Children["eggs"].Value, is.Equal("bacon"))
+}
+
+func TestNodeClone(t *testing.T) {
+	c := standardConfig.Clone()
+
+	assert.That(t, c, is.DeepEqual(standardConfig))
+
+	c.Children["db"].Children["host"].Value = "example.com"
+	assert.That(t, standardConfig.Children["db"].Children["host"].Value, is.Equal("localhost"))
+}
+
+func TestSource_String(t *testing.T) {
+	assert.That(t, Source{Loader: "env"}.String(), is.Equal("env"))
+	assert.That(t, Source{Loader: "json", File: "config.json"}.String(), is.Equal("json (config.json)"))
+	assert.That(t, Source{Loader: "yaml", File: "config.yaml", Line: 3}.String(), is.Equal("yaml (config.yaml:3)"))
+	assert.That(t, Source{File: "config.yaml", Line: 3, Column: 7}.String(), is.Equal("config.yaml:3:7"))
+}
+
+func TestNode_typedValues(t *testing.T) {
+	n, err := JSON(strings.NewReader(`{
+		"int": 1000000,
+		"big": 9007199254740993,
+		"huge": 18446744073709551615,
+		"exp": 1e6,
+		"float": 1.5,
+		"bool": true,
+		"string": "12"
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := &AppConfig{n: n}
+
+	assert.That(t, n.Children["int"].Kind(), is.Equal(KindInt))
+	assert.That(t, n.Children["exp"].Kind(), is.Equal(KindFloat))
+	assert.That(t, n.Children["bool"].Kind(), is.Equal(KindBool))
+	assert.That(t, n.Children["string"].Kind(), is.Equal(KindString))
+	assert.That(t, n.Kind(), is.Equal(KindMap))
+
+	assert.That(t, c.GetString("int"), is.Equal("1000000"))
+	assert.That(t, c.GetInt("int"), is.Equal(1000000))
+	assert.That(t, c.GetInt64("big"), is.Equal[int64](9007199254740993))
+	assert.That(t, c.GetUint64("huge"), is.Equal[uint64](18446744073709551615))
+	assert.That(t, c.GetInt("exp"), is.Equal(1000000))
+	assert.That(t, c.GetFloat64("int"), is.Equal(1000000.0))
+	assert.That(t, c.GetInt("string"), is.Equal(12))
+
+	_, err = c.GetInt64E("huge")
+	assert.That(t, errors.Is(err, strconv.ErrRange), is.Equal(true))
+
+	_, err = c.GetIntE("float")
+	assert.That(t, errors.Is(err, strconv.ErrSyntax), is.Equal(true))
+
+	_, err = c.GetUintE("bool")
+	assert.That(t, err == nil, is.Equal(false))
+}
+
+func TestConvertToNode_typedValues(t *testing.T) {
+	ts := time.Date(2022, 4, 1, 12, 0, 0, 0, time.UTC)
+
+	n, err := ConvertToNode(map[string]interface{}{
+		"int":     uint8(8),
+		"float32": float32(17.2),
+		"float64": 1e6,
+		"bool":    false,
+		"time":    ts,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.That(t, n.Children["int"].Interface(), is.DeepEqual[interface{}](int64(8)))
+	assert.That(t, n.Children["float32"].Interface(), is.DeepEqual[interface{}](17.2))
+	assert.That(t, n.Children["float32"].Value, is.Equal("17.2"))
+	assert.That(t, n.Children["float64"].Value, is.Equal("1000000"))
+	assert.That(t, n.Children["bool"].Interface(), is.DeepEqual[interface{}](false))
+	assert.That(t, n.Children["time"].Kind(), is.Equal(KindTime))
+	assert.That(t, n.Children["time"].Value, is.Equal("2022-04-01T12:00:00Z"))
+}
+
+func TestNode_null(t *testing.T) {
+	n, err := JSON(strings.NewReader(`{"db": {"host": null, "port": 5432}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := &AppConfig{n: n}
+
+	assert.That(t, n.Children["db"].Children["host"].Kind(), is.Equal(KindNull))
+	assert.That(t, c.HasKey("db.host"), is.Equal(true))
+
+	s, err := c.GetStringE("db.host")
+	assert.That(t, s, is.Equal(""))
+	assert.That(t, err, is.DeepEqual[error](nil))
+
+	i, err := c.GetIntE("db.host")
+	assert.That(t, i, is.Equal(0))
+	assert.That(t, err, is.DeepEqual[error](nil))
+
+	d, err := c.GetDurationE("db.host")
+	assert.That(t, d, is.Equal[time.Duration](0))
+	assert.That(t, err, is.DeepEqual[error](nil))
+}
+
+func TestNodeOverwriteWith_nullAndUnset(t *testing.T) {
+	n, err := ConvertToNode(map[string]interface{}{
+		"db": map[string]interface{}{
+			"host": "localhost",
+			"port": 5432,
+		},
+		"web.address": "localhost:8080",
+		"log.level":   "info",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	o, err := ConvertToNode(map[string]interface{}{
+		"db":          nil,
+		"web.address": Unset,
+		"log":         Unset,
+		"unknown":     Unset,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	n.OverwriteWith(o)
+
+	c := &AppConfig{n: n}
+
+	assert.That(t, c.HasKey("db"), is.Equal(true))
+	assert.That(t, n.Children["db"].Kind(), is.Equal(KindNull))
+	assert.That(t, c.HasKey("db.host"), is.Equal(false))
+	assert.That(t, c.HasKey("web"), is.Equal(true))
+	assert.That(t, c.HasKey("web.address"), is.Equal(false))
+	assert.That(t, c.HasKey("log"), is.Equal(false))
+	assert.That(t, c.HasKey("unknown"), is.Equal(false))
+}
@@ -83,5 +83,5 @@ func ExampleAppConfig_Bind_map() {
 	fmt.Printf("%v\n", config)
 
 	// Output:
-	// map[backends:map[0:map[host:alpha port:8080 tags:map[0:a 1:1]] 1:map[host:beta port:8081 tags:map[0:b 1:2]]] db:map[host:localhost password:secret port:3306 type:mysql user:test] web:map[address:localhost:8080 authorize:true timeout:2s]]
+	// map[backends:[map[host:alpha port:8080 tags:[a 1]] map[host:beta port:8081 tags:[b 2]]] db:map[host:localhost password:secret port:3306 type:mysql user:test] web:map[address:localhost:8080 authorize:true timeout:2s]]
 }
@@ -0,0 +1,212 @@
+package appconf
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+// Properties parses the content of r in the format of Java .properties files and converts it to a Node
+// tree. Dotted keys such as db.host are mapped to nested keys.
+//
+// Key and value are separated by =, : or whitespace. Lines starting with # or ! are comments. A line ending
+// with a backslash is continued on the next line with the next line's leading whitespace removed. Keys and
+// values may contain the escape sequences \t, \n, \r, \f and \uXXXX; a backslash preceding any other
+// character is removed. Syntax errors are reported as a *ParseError.
+func Properties(r io.Reader) (*Node, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	n := NewNode("")
+
+	for _, l := range readLogicalLines(string(data), "#!") {
+		text := strings.TrimLeft(l.text, " \t\f")
+
+		end := 0
+		for end < len(text) && !strings.ContainsRune("=: \t\f", rune(text[end])) {
+			if text[end] == '\\' {
+				end++
+			}
+			end++
+		}
+		if end > len(text) {
+			end = len(text)
+		}
+
+		rawValue := strings.TrimLeft(text[end:], " \t\f")
+		if rawValue != "" && (rawValue[0] == '=' || rawValue[0] == ':') {
+			rawValue = strings.TrimLeft(rawValue[1:], " \t\f")
+		}
+
+		key, err := unescapeProperty(text[:end], false)
+		if err != nil {
+			return nil, l.error(text, err)
+		}
+		if key == "" {
+			return nil, l.error(text, fmt.Errorf("%w: missing key", ErrUnsupportedValue))
+		}
+
+		value, err := unescapeProperty(rawValue, false)
+		if err != nil {
+			return nil, l.error(rawValue, err)
+		}
+
+		v := NewNode(value)
+		v.source.Line, v.source.Column = l.line, l.column(len(l.text)-len(rawValue))
+		n.set(ParseKeyPath(key), v)
+	}
+
+	n.annotate(Source{Loader: "properties"})
+	return n, nil
+}
+
+// PropertiesFile creates a Loader which loads configuration from the .properties file name. See Properties
+// for a description of the format.
+func PropertiesFile(name string, mandatory bool) Loader {
+	return File(name, mandatory, Properties)
+}
+
+// logicalLine is a line of a .properties or INI file with all continuation lines appended.
+type logicalLine struct {
+	// text is the line's content.
+	text string
+	// line is the 1-based number of the line's first physical line.
+	line int
+	// first is the length of the part of text read from the first physical line.
+	first int
+}
+
+// column returns the 1-based column of the byte at offset in l's text or zero if the byte has not been read
+// from the first physical line.
+func (l logicalLine) column(offset int) int {
+	if offset >= l.first {
+		return 0
+	}
+	return offset + 1
+}
+
+// error creates a *ParseError describing err found at s, which is the remainder of l's text.
+func (l logicalLine) error(s string, err error) error {
+	return &ParseError{
+		Source: Source{Line: l.line, Column: l.column(len(l.text) - len(s))},
+		Err:    err,
+	}
+}
+
+// readLogicalLines splits data into logical lines. Blank lines and comment lines starting with one of the
+// characters in commentChars are skipped. A line ending with an odd number of backslashes is joined with the
+// next line with the next line's leading whitespace removed.
+func readLogicalLines(data, commentChars string) []logicalLine {
+	var result []logicalLine
+
+	lines := strings.Split(strings.ReplaceAll(data, "\r\n", "\n"), "\n")
+	for i := 0; i < len(lines); i++ {
+		trimmed := strings.TrimLeft(lines[i], " \t\f")
+		if trimmed == "" || strings.ContainsRune(commentChars, rune(trimmed[0])) {
+			continue
+		}
+
+		l := logicalLine{
+			text:  lines[i],
+			line:  i + 1,
+			first: -1,
+		}
+		for continuesLine(l.text) {
+			l.text = l.text[:len(l.text)-1]
+			if l.first < 0 {
+				l.first = len(l.text)
+			}
+			if i+1 == len(lines) {
+				break
+			}
+			i++
+			l.text += strings.TrimLeft(lines[i], " \t\f")
+		}
+		if l.first < 0 {
+			l.first = len(l.text)
+		}
+
+		result = append(result, l)
+	}
+
+	return result
+}
+
+// continuesLine reports whether s ends with an odd number of backslashes.
+func continuesLine(s string) bool {
+	n := 0
+	for i := len(s) - 1; i >= 0 && s[i] == '\\'; i-- {
+		n++
+	}
+	return n%2 == 1
+}
+
+// unescapeProperty replaces the escape sequences in s. A backslash preceding a character not forming one of
+// the escape sequences \t, \n, \r, \f or \uXXXX is removed. If keepUnknown is set, the backslash is only
+// removed before one of the characters \, ;, #, ", = and :.
+func unescapeProperty(s string, keepUnknown bool) (string, error) {
+	if !strings.Contains(s, `\`) {
+		return s, nil
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' {
+			b.WriteByte(s[i])
+			continue
+		}
+
+		i++
+		if i == len(s) {
+			break
+		}
+
+		switch s[i] {
+		case 't':
+			b.WriteByte('\t')
+		case 'n':
+			b.WriteByte('\n')
+		case 'r':
+			b.WriteByte('\r')
+		case 'f':
+			b.WriteByte('\f')
+		case 'u':
+			r, err := parseUnicodeEscape(s[i+1:])
+			if err != nil {
+				return "", err
+			}
+			i += 4
+
+			if utf16.IsSurrogate(r) && strings.HasPrefix(s[i+1:], `\u`) {
+				if low, err := parseUnicodeEscape(s[i+3:]); err == nil {
+					r = utf16.DecodeRune(r, low)
+					i += 6
+				}
+			}
+			b.WriteRune(r)
+		default:
+			if keepUnknown && !strings.ContainsRune(`\;#"=:`, rune(s[i])) {
+				b.WriteByte('\\')
+			}
+			b.WriteByte(s[i])
+		}
+	}
+
+	return b.String(), nil
+}
+
+// parseUnicodeEscape parses the four hex digits at the beginning of s.
+func parseUnicodeEscape(s string) (rune, error) {
+	if len(s) < 4 {
+		return 0, fmt.Errorf("%w: invalid unicode escape: \\u%s", ErrUnsupportedValue, s)
+	}
+	v, err := strconv.ParseUint(s[:4], 16, 16)
+	if err != nil {
+		return 0, fmt.Errorf("%w: invalid unicode escape: \\u%s", ErrUnsupportedValue, s[:4])
+	}
+	return rune(v), nil
+}
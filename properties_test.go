@@ -0,0 +1,51 @@
+package appconf
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/halimath/assertthat-go/assert"
+	"github.com/halimath/assertthat-go/is"
+)
+
+func TestProperties(t *testing.T) {
+	got, err := Properties(strings.NewReader(`# comment
+! another comment
+db.host = localhost
+db.port:5432
+db.user admin
+web.motd = Hello \
+           world
+web.greeting=Gr\u00fc\u00dfe \ud83d\ude00
+key\ with\ spaces = a\=b\tc
+empty
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := &AppConfig{n: got}
+	assert.That(t, c.GetString("db.host"), is.Equal("localhost"))
+	assert.That(t, c.GetInt("db.port"), is.Equal(5432))
+	assert.That(t, c.GetString("db.user"), is.Equal("admin"))
+	assert.That(t, c.GetString("web.motd"), is.Equal("Hello world"))
+	assert.That(t, c.GetString("web.greeting"), is.Equal("Grüße 😀"))
+	assert.That(t, c.GetString("key with spaces"), is.Equal("a=b\tc"))
+	assert.That(t, c.HasKey("empty"), is.Equal(true))
+
+	assert.That(t, got.resolve(ParseKeyPath("db.port")).Source(), is.Equal(Source{Loader: "properties", Line: 4, Column: 9}))
+	assert.That(t, got.resolve(ParseKeyPath("web.motd")).Source(), is.Equal(Source{Loader: "properties", Line: 6, Column: 12}))
+}
+
+func TestProperties_errors(t *testing.T) {
+	_, err := Properties(strings.NewReader("a = 1\nb = \\u00zz\n"))
+
+	var parseErr *ParseError
+	assert.That(t, errors.As(err, &parseErr), is.Equal(true))
+	assert.That(t, err.Error(), is.Equal(`line 2, column 5: unsupported value: invalid unicode escape: \u00zz`))
+}
+
+func TestPropertiesFile(t *testing.T) {
+	assertLoader(t, PropertiesFile("./testdata/config.properties", true))
+}
@@ -0,0 +1,165 @@
+package appconf
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sync"
+)
+
+// redacted replaces secret values in all output.
+const redacted = "***"
+
+// Secret is a string value that must not be revealed, such as a password or an API token. Secret values
+// are formatted as *** by the fmt package and marshaled as *** by the JSON, YAML and TOML encoders. Use Value
+// to access the actual value.
+//
+// Bind populates fields of type Secret like string fields and marks their keys as secret (see
+// WithSecretKeys). Passing a Secret to Static or AppConfig.Set marks the value as secret as well.
+type Secret string
+
+// Value returns the actual value of s.
+func (s Secret) Value() string {
+	return string(s)
+}
+
+func (s Secret) String() string {
+	return redacted
+}
+
+// Format implements fmt.Formatter and prints *** for all verbs.
+func (s Secret) Format(f fmt.State, verb rune) {
+	io.WriteString(f, redacted)
+}
+
+func (s Secret) MarshalJSON() ([]byte, error) {
+	return json.Marshal(redacted)
+}
+
+func (s Secret) MarshalYAML() (interface{}, error) {
+	return redacted, nil
+}
+
+func (s Secret) MarshalText() ([]byte, error) {
+	return []byte(redacted), nil
+}
+
+var secretType = reflect.TypeOf(Secret(""))
+
+// isSecretType reports whether t is Secret or a pointer to, slice, array or map of Secret values.
+func isSecretType(t reflect.Type) bool {
+	for {
+		if t == secretType {
+			return true
+		}
+		switch t.Kind() {
+		case reflect.Pointer, reflect.Slice, reflect.Array, reflect.Map:
+			t = t.Elem()
+		default:
+			return false
+		}
+	}
+}
+
+// WithSecretKeys creates an Option that marks the values stored under the given keys as secret. A key path
+// element of * matches every key, i.e. *.password selects the password of every top-level key. Marking a key
+// also marks all nested keys.
+//
+// Secret values are replaced with *** when the configuration is written (i.e. with WriteJSON), explained
+// or reported in a BindError. Struct fields tagged with the secret option or of type Secret mark their keys
+// when bound.
+func WithSecretKeys(keys ...string) Option {
+	return func(c *AppConfig) {
+		for _, k := range keys {
+			c.secrets.add(ParseKeyPath(k))
+		}
+	}
+}
+
+// secretKeys holds the key path patterns of secret values. It is shared by an AppConfig and all of its
+// snapshots and sub-configurations. All methods may be called on a nil secretKeys.
+type secretKeys struct {
+	lock     sync.RWMutex
+	patterns []KeyPath
+}
+
+// add adds pattern unless it is already contained in s.
+func (s *secretKeys) add(pattern KeyPath) {
+	if s == nil {
+		return
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for _, p := range s.patterns {
+		if p.Join() == pattern.Join() {
+			return
+		}
+	}
+	s.patterns = append(s.patterns, pattern)
+}
+
+// matches reports whether path or one of its parents matches a pattern of s.
+func (s *secretKeys) matches(path KeyPath) bool {
+	if s == nil {
+		return false
+	}
+
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	for _, p := range s.patterns {
+		if matchesKeyPrefix(p, path) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesKeyPrefix reports whether pattern matches path or one of its parents.
+func matchesKeyPrefix(pattern, path KeyPath) bool {
+	if len(pattern) > len(path) {
+		return false
+	}
+	for i, k := range pattern {
+		if k != wildcardKey && k != path[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// redactor decides which values of a configuration tree rooted at the key path root are secret.
+type redactor struct {
+	secrets *secretKeys
+	root    KeyPath
+}
+
+// redactor returns the redactor for c's values.
+func (c *AppConfig) redactor() redactor {
+	return redactor{
+		secrets: c.secrets,
+		root:    c.path,
+	}
+}
+
+// redacts reports whether the scalar value n stored under path (relative to r's root) must be redacted.
+func (r redactor) redacts(path KeyPath, n *Node) bool {
+	switch n.Kind() {
+	case KindMap, KindList, KindNull, KindUnset:
+		return false
+	}
+	return n.secret || r.isSecretKey(path)
+}
+
+// isSecretKey reports whether the key path (relative to r's root) has been marked as secret.
+func (r redactor) isSecretKey(path KeyPath) bool {
+	return r.secrets.matches(append(r.root[:len(r.root):len(r.root)], path...))
+}
+
+// mark marks the key path (relative to r's root) as secret.
+func (r redactor) mark(path KeyPath) {
+	r.secrets.add(append(r.root[:len(r.root):len(r.root)], path...))
+}
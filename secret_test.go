@@ -0,0 +1,228 @@
+package appconf
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/halimath/assertthat-go/assert"
+	"github.com/halimath/assertthat-go/is"
+	"gopkg.in/yaml.v3"
+)
+
+func TestSecret(t *testing.T) {
+	s := Secret("s3cr3t")
+
+	assert.That(t, s.Value(), is.Equal("s3cr3t"))
+	assert.That(t, fmt.Sprint(s), is.Equal("***"))
+	assert.That(t, fmt.Sprintf("%v %s %q %#v %d", s, s, s, s, s), is.Equal("*** *** *** *** ***"))
+
+	j, err := json.Marshal(map[string]interface{}{"password": s})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.That(t, string(j), is.Equal(`{"password":"***"}`))
+
+	y, err := yaml.Marshal(map[string]interface{}{"password": s})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.That(t, string(y), is.Equal("password: '***'\n"))
+}
+
+func TestWithSecretKeys(t *testing.T) {
+	c, err := NewWithOptions([]Loader{
+		Static(map[string]interface{}{
+			"db.host":            "localhost",
+			"db.password":        "s3cr3t",
+			"api.token":          "t0k3n",
+			"credentials.user":   "admin",
+			"credentials.hashes": []interface{}{"a", "b"},
+		}),
+	}, WithSecretKeys("*.password", "api.token", "credentials"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.That(t, c.GetString("db.password"), is.Equal("s3cr3t"))
+
+	var b strings.Builder
+	if err := c.WriteJSON(&b); err != nil {
+		t.Fatal(err)
+	}
+	assert.That(t, b.String(), is.Equal(`{
+  "api": {
+    "token": "***"
+  },
+  "credentials": {
+    "hashes": [
+      "***",
+      "***"
+    ],
+    "user": "***"
+  },
+  "db": {
+    "host": "localhost",
+    "password": "***"
+  }
+}
+`))
+
+	b.Reset()
+	if err := c.Sub("db").WriteEnv(&b, "DB"); err != nil {
+		t.Fatal(err)
+	}
+	assert.That(t, b.String(), is.Equal("DB_HOST=localhost\nDB_PASSWORD=***\n"))
+
+	assert.That(t, c.Explain("db.password").String(), is.Equal("db.password\n* static: \"***\""))
+}
+
+func TestSecret_values(t *testing.T) {
+	t.Setenv("APP_DB_PASSWORD", "from-env")
+
+	c, err := New(
+		Static(map[string]interface{}{
+			"db.password": Secret("s3cr3t"),
+		}),
+		Env("APP"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.That(t, c.GetString("db.password"), is.Equal("from-env"))
+
+	var b strings.Builder
+	if err := c.WriteYAML(&b); err != nil {
+		t.Fatal(err)
+	}
+	assert.That(t, b.String(), is.Equal("db:\n  password: '***'\n"))
+	assert.That(t, c.Explain("db.password").String(), is.Equal("db.password\n* env: \"***\"\n  static: \"***\""))
+}
+
+func TestGet_secrets(t *testing.T) {
+	c, err := NewWithOptions([]Loader{
+		Static(map[string]interface{}{
+			"db.port":  "abc",
+			"db.ports": []interface{}{"1", "x2"},
+		}),
+	}, WithSecretKeys("db.port", "db.ports"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = Get[int](c, "db.port")
+	assert.That(t, err.Error(), is.Equal(`db.port: invalid int "***"`))
+
+	_, err = Get[[]int](c, "db.ports")
+	assert.That(t, err.Error(), is.Equal(`db.ports.1: invalid int "***" (field [1])`))
+
+	_, err = Get[int](c.Sub("db"), "port")
+	assert.That(t, err.Error(), is.Equal(`port: invalid int "***"`))
+
+	r := NewRegistry()
+	k := NewKey[int]("db.port", InRegistry(r))
+
+	_, err = k.GetE(c)
+	assert.That(t, err.Error(), is.Equal(`db.port: invalid int "***"`))
+
+	err = r.Validate(c)
+	assert.That(t, err.Error(), is.Equal(`db.port: invalid int "***"`))
+}
+
+func TestAppConfig_Bind_secretKeysInMaps(t *testing.T) {
+	c, err := NewWithOptions([]Loader{
+		Static(map[string]interface{}{
+			"db.password": "hunter2",
+			"db.port":     "abc",
+			"tokens":      []interface{}{"t0k3n"},
+		}),
+	}, WithSecretKeys("db.password", "tokens"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var config struct {
+		DB     map[string]int
+		Tokens []int
+	}
+	err = c.Bind(&config)
+
+	assert.That(t, err.Error(), is.Equal(strings.Join([]string{
+		`db.password: invalid int "***" (field DB["password"])`,
+		`db.port: invalid int "abc": invalid syntax (field DB["port"])`,
+		`tokens.0: invalid int "***" (field Tokens[0])`,
+	}, "; ")))
+}
+
+func TestAppConfig_Bind_validateSecrets(t *testing.T) {
+	c, err := New(Static(map[string]interface{}{
+		"password": "hunter",
+		"color":    "red",
+		"url":      "https://example.com",
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var config struct {
+		Password Secret `appconf:",regexp=^[a-z]+$"`
+		Color    Secret `appconf:",oneof=red blue"`
+		URL      Secret `appconf:",url"`
+	}
+	if err := c.Bind(&config); err != nil {
+		t.Fatal(err)
+	}
+	assert.That(t, config.Password.Value(), is.Equal("hunter"))
+
+	var invalid struct {
+		Password Secret `appconf:",regexp=^[0-9]+$"`
+	}
+	err = c.Bind(&invalid)
+	assert.That(t, err.Error(), is.Equal("password: does not satisfy regexp=^[0-9]+$ (field Password)"))
+}
+
+func TestAppConfig_Bind_secrets(t *testing.T) {
+	c, err := New(Static(map[string]interface{}{
+		"db.user":     "admin",
+		"db.password": "s3cr3t",
+		"db.pin":      "12ab",
+		"api.token":   "t0k3n",
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var config struct {
+		DB struct {
+			User     string
+			Password string `appconf:",secret,min=8"`
+			PIN      int    `appconf:"pin,secret"`
+		}
+		API struct {
+			Token Secret
+		}
+	}
+	err = c.Bind(&config)
+
+	assert.That(t, config.DB.Password, is.Equal("s3cr3t"))
+	assert.That(t, config.API.Token.Value(), is.Equal("t0k3n"))
+
+	var bindErrs BindErrors
+	if !errors.As(err, &bindErrs) {
+		t.Fatalf("expected BindErrors but got %v", err)
+	}
+	assert.That(t, err.Error(), is.Equal(strings.Join([]string{
+		"db.password: does not satisfy min=8 (field DB.Password)",
+		`db.pin: invalid int "***" (field DB.PIN)`,
+	}, "; ")))
+	assert.That(t, strings.Contains(fmt.Sprintf("%+v", bindErrs[1]), "12ab"), is.Equal(false))
+
+	var b strings.Builder
+	if err := c.WriteEnv(&b, ""); err != nil {
+		t.Fatal(err)
+	}
+	assert.That(t, b.String(), is.Equal("API_TOKEN=***\nDB_PASSWORD=***\nDB_PIN=***\nDB_USER=admin\n"))
+}
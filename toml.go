@@ -0,0 +1,209 @@
+package appconf
+
+import (
+	"strconv"
+	"strings"
+)
+
+// tomlPosition records the position of the value stored under path in a TOML document.
+type tomlPosition struct {
+	path         KeyPath
+	line, column int
+}
+
+// scanTOMLPositions scans the TOML document data for table headers and key/value pairs and returns the
+// positions of the values they define. data is expected to be a valid TOML document. Values nested in arrays
+// and inline tables are not reported.
+func scanTOMLPositions(data []byte) []tomlPosition {
+	var (
+		positions []tomlPosition
+		table     KeyPath
+		arrays    = make(map[string]int)
+		value     tomlValueState
+	)
+
+	for i, line := range strings.Split(string(data), "\n") {
+		if value.open() {
+			value.scan(line)
+			continue
+		}
+
+		trimmed := strings.TrimLeft(line, " \t")
+		column := len(line) - len(trimmed) + 1
+
+		switch {
+		case trimmed == "" || trimmed[0] == '#':
+			continue
+
+		case strings.HasPrefix(trimmed, "[["):
+			parts, _ := parseTOMLKey(trimmed[2:])
+			path := resolveTOMLTable(parts[:len(parts)-1], arrays)
+			path = append(path, tomlKeyPath(parts[len(parts)-1:])...)
+
+			name := path.Join()
+			table = append(path, indexKey(arrays[name]))
+			arrays[name]++
+
+		case trimmed[0] == '[':
+			parts, _ := parseTOMLKey(trimmed[1:])
+			table = resolveTOMLTable(parts, arrays)
+
+		default:
+			parts, rest := parseTOMLKey(trimmed)
+			if !strings.HasPrefix(rest, "=") {
+				continue
+			}
+			rest = strings.TrimLeft(rest[1:], " \t")
+			positions = append(positions, tomlPosition{
+				path:   append(table[:len(table):len(table)], tomlKeyPath(parts)...),
+				line:   i + 1,
+				column: len(line) - len(rest) + 1,
+			})
+			value.scan(rest)
+			continue
+		}
+
+		positions = append(positions, tomlPosition{
+			path:   table,
+			line:   i + 1,
+			column: column,
+		})
+	}
+
+	return positions
+}
+
+// resolveTOMLTable converts the key parts of a table header to a KeyPath. Arrays of tables contained in the
+// path are resolved to their last element according to the counts recorded in arrays.
+func resolveTOMLTable(parts []string, arrays map[string]int) KeyPath {
+	var path KeyPath
+	for _, p := range parts {
+		path = append(path, tomlKeyPath([]string{p})...)
+		if n, ok := arrays[path.Join()]; ok {
+			path = append(path, indexKey(n-1))
+		}
+	}
+	return path
+}
+
+// tomlKeyPath converts the parts of a TOML key to a KeyPath the same way ConvertToNode converts map keys.
+func tomlKeyPath(parts []string) KeyPath {
+	var path KeyPath
+	for _, p := range parts {
+		path = append(path, ParseKeyPath(p)...)
+	}
+	return path
+}
+
+// parseTOMLKey parses the (possibly dotted) TOML key at the beginning of s. It returns the key's parts and
+// the remainder of s following the key.
+func parseTOMLKey(s string) ([]string, string) {
+	var parts []string
+
+	for {
+		s = strings.TrimLeft(s, " \t")
+
+		var part string
+		switch {
+		case strings.HasPrefix(s, `"`):
+			end := skipTOMLString(s, 1, '"')
+			part = s[:end]
+			if unquoted, err := strconv.Unquote(part); err == nil {
+				part = unquoted
+			}
+			s = s[end:]
+
+		case strings.HasPrefix(s, "'"):
+			end := skipTOMLString(s, 1, '\'')
+			part = strings.Trim(s[:end], "'")
+			s = s[end:]
+
+		default:
+			end := strings.IndexFunc(s, func(r rune) bool {
+				return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' || r == '-')
+			})
+			if end < 0 {
+				end = len(s)
+			}
+			part, s = s[:end], s[end:]
+		}
+
+		parts = append(parts, part)
+
+		s = strings.TrimLeft(s, " \t")
+		if !strings.HasPrefix(s, ".") {
+			return parts, s
+		}
+		s = s[1:]
+	}
+}
+
+// skipTOMLString returns the index following the closing quote q of the single-line string in s, which
+// starts at index i. Basic strings (quoted with ") may contain escaped quotes.
+func skipTOMLString(s string, i int, q byte) int {
+	for i < len(s) {
+		switch s[i] {
+		case '\\':
+			if q == '"' {
+				i++
+			}
+		case q:
+			return i + 1
+		}
+		i++
+	}
+	return len(s)
+}
+
+// tomlValueState tracks the values spanning multiple lines while scanning a TOML document.
+type tomlValueState struct {
+	// delim is the delimiter of an open multi-line string.
+	delim string
+	// depth is the nesting depth of open arrays and inline tables.
+	depth int
+}
+
+// open reports whether a value is still open at the end of the scanned input.
+func (st *tomlValueState) open() bool {
+	return st.delim != "" || st.depth > 0
+}
+
+// scan scans s, which is (part of) a value, and updates st.
+func (st *tomlValueState) scan(s string) {
+	for i := 0; i < len(s); {
+		if st.delim != "" {
+			switch {
+			case st.delim == `"""` && s[i] == '\\':
+				i += 2
+			case strings.HasPrefix(s[i:], st.delim):
+				i += len(st.delim)
+				// The closing delimiter may be preceded by up to two quotes belonging to the string.
+				for i < len(s) && s[i] == st.delim[0] {
+					i++
+				}
+				st.delim = ""
+			default:
+				i++
+			}
+			continue
+		}
+
+		switch c := s[i]; {
+		case strings.HasPrefix(s[i:], `"""`) || strings.HasPrefix(s[i:], `'''`):
+			st.delim = s[i : i+3]
+			i += 3
+		case c == '"' || c == '\'':
+			i = skipTOMLString(s, i+1, c)
+		case c == '[' || c == '{':
+			st.depth++
+			i++
+		case c == ']' || c == '}':
+			st.depth--
+			i++
+		case c == '#':
+			return
+		default:
+			i++
+		}
+	}
+}
@@ -0,0 +1,265 @@
+package appconf
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+)
+
+// TypedKey is a config key declared with a fixed type, an optional default value and a description. Typed
+// keys are usually declared as package level variables using NewKey and read with Get.
+type TypedKey[T any] struct {
+	name string
+	info KeyInfo
+	def  T
+}
+
+// KeyInfo describes a declared TypedKey.
+type KeyInfo struct {
+	// Name is the key's name as given to NewKey.
+	Name string
+	// Type is the type of the key's value.
+	Type reflect.Type
+	// Description describes the key's purpose.
+	Description string
+	// HasDefault signals whether Default has been given.
+	HasDefault bool
+	// Default is the key's default value or nil if no default has been given.
+	Default interface{}
+	// Required signals whether the key must be defined if no default is given.
+	Required bool
+}
+
+// KeyOption defines a function type to customize a TypedKey created with NewKey.
+type KeyOption func(*keyOptions)
+
+type keyOptions struct {
+	info     KeyInfo
+	registry *Registry
+}
+
+// Default creates a KeyOption that sets the key's default value. v must be assignable or convertible to the
+// key's type; NewKey panics otherwise.
+func Default(v interface{}) KeyOption {
+	return func(o *keyOptions) {
+		o.info.HasDefault = true
+		o.info.Default = v
+	}
+}
+
+// Describe creates a KeyOption that sets the key's description.
+func Describe(description string) KeyOption {
+	return func(o *keyOptions) {
+		o.info.Description = description
+	}
+}
+
+// Required creates a KeyOption that makes Registry.Validate report the key if it is not defined and has no
+// default value.
+func Required() KeyOption {
+	return func(o *keyOptions) {
+		o.info.Required = true
+	}
+}
+
+// InRegistry creates a KeyOption that registers the key with r instead of DefaultRegistry.
+func InRegistry(r *Registry) KeyOption {
+	return func(o *keyOptions) {
+		o.registry = r
+	}
+}
+
+// NewKey declares a key of type T named name and registers it with DefaultRegistry (unless InRegistry is
+// given). NewKey panics if the default value cannot be converted to T or if the registry already contains a
+// key with the same name.
+func NewKey[T any](name string, opts ...KeyOption) *TypedKey[T] {
+	var def T
+
+	o := keyOptions{
+		info: KeyInfo{
+			Name: name,
+			Type: reflect.TypeOf(&def).Elem(),
+		},
+		registry: DefaultRegistry,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	if o.info.HasDefault {
+		var err error
+		def, err = convertDefault[T](name, o.info.Default)
+		if err != nil {
+			panic(fmt.Sprintf("appconf: invalid default for key %s: %s", name, err))
+		}
+		o.info.Default = def
+	}
+
+	k := &TypedKey[T]{
+		name: name,
+		info: o.info,
+		def:  def,
+	}
+
+	o.registry.register(k)
+
+	return k
+}
+
+// convertDefault converts the default value v of the key name to T. Values of other types are converted
+// the same way as a config value would be.
+func convertDefault[T any](name string, v interface{}) (T, error) {
+	if d, ok := v.(T); ok {
+		return d, nil
+	}
+
+	n, err := createNodeFromValue(v)
+	if err != nil {
+		var d T
+		return d, err
+	}
+
+	return convertTo[T](n, name, redactor{})
+}
+
+// Name returns the name of k.
+func (k *TypedKey[T]) Name() string {
+	return k.name
+}
+
+// Info returns the description of k.
+func (k *TypedKey[T]) Info() KeyInfo {
+	return k.info
+}
+
+// Get returns the value of k from c. It returns k's default value if the key is not defined or its value
+// cannot be converted to T.
+func (k *TypedKey[T]) Get(c *AppConfig) T {
+	v, err := k.GetE(c)
+	if err != nil {
+		return k.def
+	}
+	return v
+}
+
+// GetE returns the value of k from c. If the key is not defined or its value is null, k's default value is
+// returned. An error wrapping ErrNoSuchKey is returned if k has no default value and is not defined.
+func (k *TypedKey[T]) GetE(c *AppConfig) (T, error) {
+	if k.info.HasDefault {
+		return GetOr(c, k.name, k.def)
+	}
+	return Get[T](c, k.name)
+}
+
+// validate checks the value of k in c. It reports a failed validation for required keys that are not
+// defined or an error converting the value.
+func (k *TypedKey[T]) validate(c *AppConfig) error {
+	n, err := c.get(k.name)
+	if err != nil || n.kind == KindNull {
+		if k.info.Required && !k.info.HasDefault {
+			return newBindError(k.info.Type, ParseKeyPath(k.name), "", &ValidationError{
+				Key:     ParseKeyPath(k.name).Join(),
+				Rule:    ruleRequired,
+				Message: "is required",
+			})
+		}
+		return nil
+	}
+
+	_, err = convertTo[T](n, k.name, c.redactor())
+	return err
+}
+
+// registeredKey is implemented by all instantiations of TypedKey.
+type registeredKey interface {
+	Name() string
+	Info() KeyInfo
+	validate(c *AppConfig) error
+}
+
+// Registry collects declared TypedKeys.
+type Registry struct {
+	lock sync.RWMutex
+	keys map[string]registeredKey
+}
+
+// DefaultRegistry is the Registry keys are registered with by default.
+var DefaultRegistry = NewRegistry()
+
+// NewRegistry creates a new, empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		keys: make(map[string]registeredKey),
+	}
+}
+
+func (r *Registry) register(k registeredKey) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	name := ParseKeyPath(k.Name()).Join()
+	if _, ok := r.keys[name]; ok {
+		panic(fmt.Sprintf("appconf: key %s already registered", k.Name()))
+	}
+	r.keys[name] = k
+}
+
+// Keys returns the descriptions of all keys registered with r ordered by name.
+func (r *Registry) Keys() []KeyInfo {
+	keys := r.sortedKeys()
+	infos := make([]KeyInfo, len(keys))
+	for i, k := range keys {
+		infos[i] = k.Info()
+	}
+	return infos
+}
+
+// sortedKeys returns all keys registered with r ordered by name.
+func (r *Registry) sortedKeys() []registeredKey {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	keys := make([]registeredKey, 0, len(r.keys))
+	for _, k := range r.keys {
+		keys = append(keys, k)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].Name() < keys[j].Name()
+	})
+
+	return keys
+}
+
+// Validate checks c for all keys registered with r. It reports values that cannot be converted to their
+// key's type and required keys that are not defined as BindErrors.
+func (r *Registry) Validate(c *AppConfig) error {
+	var errs BindErrors
+
+	for _, k := range r.sortedKeys() {
+		err := k.validate(c)
+		if err == nil {
+			continue
+		}
+
+		var (
+			bindErrs BindErrors
+			bindErr  *BindError
+		)
+		switch {
+		case errors.As(err, &bindErrs):
+			errs = append(errs, bindErrs...)
+		case errors.As(err, &bindErr):
+			errs = append(errs, bindErr)
+		default:
+			errs = append(errs, newBindError(k.Info().Type, ParseKeyPath(k.Name()), "", err))
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package appconf
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/halimath/assertthat-go/assert"
+	"github.com/halimath/assertthat-go/is"
+)
+
+func TestTypedKey(t *testing.T) {
+	r := NewRegistry()
+
+	var (
+		port    = NewKey[int64]("db.port", Default(5432), Describe("Database port"), InRegistry(r))
+		timeout = NewKey[time.Duration]("web.timeout", InRegistry(r))
+		user    = NewKey[string]("db.username", Default("app"), InRegistry(r))
+		missing = NewKey[int]("db.missing", InRegistry(r))
+	)
+
+	c, err := New(JSONFile("./testdata/config.json", true))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.That(t, port.Get(c), is.Equal(int64(3306)))
+	assert.That(t, timeout.Get(c), is.Equal(2*time.Second))
+	assert.That(t, user.Get(c), is.Equal("app"))
+	assert.That(t, missing.Get(c), is.Equal(0))
+
+	_, err = missing.GetE(c)
+	assert.That(t, errors.Is(err, ErrNoSuchKey), is.Equal(true))
+
+	assert.That(t, r.Keys(), is.DeepEqual([]KeyInfo{
+		{Name: "db.missing", Type: reflect.TypeOf(0)},
+		{Name: "db.port", Type: reflect.TypeOf(int64(0)), Description: "Database port", HasDefault: true, Default: int64(5432)},
+		{Name: "db.username", Type: reflect.TypeOf(""), HasDefault: true, Default: "app"},
+		{Name: "web.timeout", Type: reflect.TypeOf(time.Duration(0))},
+	}))
+}
+
+func TestTypedKey_invalidDefault(t *testing.T) {
+	defer func() {
+		assert.That(t, recover() != nil, is.Equal(true))
+	}()
+	NewKey[int]("port", Default("http"), InRegistry(NewRegistry()))
+}
+
+func TestTypedKey_duplicate(t *testing.T) {
+	r := NewRegistry()
+	NewKey[int]("db.port", InRegistry(r))
+
+	defer func() {
+		assert.That(t, recover() != nil, is.Equal(true))
+	}()
+	NewKey[string]("DB.Port", InRegistry(r))
+}
+
+func TestRegistry_Validate(t *testing.T) {
+	r := NewRegistry()
+	NewKey[string]("db.host", Required(), InRegistry(r))
+	NewKey[int]("db.user", InRegistry(r))
+	NewKey[uint8]("db.port", InRegistry(r))
+	NewKey[string]("db.name", Required(), InRegistry(r))
+	NewKey[int]("db.pool", Required(), Default(10), InRegistry(r))
+
+	c, err := New(JSONFile("./testdata/config.json", true))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = r.Validate(c)
+
+	var bindErrs BindErrors
+	if !errors.As(err, &bindErrs) {
+		t.Fatalf("expected BindErrors but got %v", err)
+	}
+	assert.That(t, len(bindErrs), is.Equal(3))
+	assert.That(t, bindErrs[0].Key, is.Equal("db.name"))
+	assert.That(t, bindErrs[1].Key, is.Equal("db.port"))
+	assert.That(t, bindErrs[2].Key, is.Equal("db.user"))
+	assert.That(t, errors.Is(bindErrs[1], ErrInvalidValue), is.Equal(true))
+
+	c, err = New(JSONFile("./testdata/config.json", true), Static(map[string]interface{}{
+		"db": map[string]interface{}{
+			"port": 22,
+			"user": 1,
+		},
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = r.Validate(c)
+	assert.That(t, validationErrors(t, err), is.DeepEqual([]*ValidationError{
+		{Key: "db.name", Rule: "required", Message: "is required"},
+	}))
+
+	if err := c.Set("db.name", "app"); err != nil {
+		t.Fatal(err)
+	}
+	assert.That(t, r.Validate(c), is.DeepEqual[error](nil))
+}
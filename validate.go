@@ -0,0 +1,240 @@
+package appconf
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrValidation is reported by all errors describing a failed validation.
+	ErrValidation = errors.New("validation failed")
+)
+
+// ValidationError describes a single validation rule that failed for a bound value. Bind reports it as the
+// cause of a BindError.
+type ValidationError struct {
+	// Key is the full key path of the value.
+	Key string
+	// Rule is the failed rule as given in the struct tag, i.e. min=1.
+	Rule string
+	// Message describes the failure.
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Key, e.Message)
+}
+
+func (e *ValidationError) Unwrap() error {
+	return ErrValidation
+}
+
+const (
+	ruleRequired = "required"
+	ruleNonEmpty = "nonempty"
+	ruleURL      = "url"
+	ruleMin      = "min"
+	ruleMax      = "max"
+	ruleOneOf    = "oneof"
+	ruleRegexp   = "regexp"
+)
+
+// validationRuleNames contains the names of all supported validation rules.
+var validationRuleNames = map[string]struct{}{
+	ruleRequired: {},
+	ruleNonEmpty: {},
+	ruleURL:      {},
+	ruleMin:      {},
+	ruleMax:      {},
+	ruleOneOf:    {},
+	ruleRegexp:   {},
+}
+
+// validationRule implements a single validation rule given in a struct field's tag.
+type validationRule struct {
+	name  string
+	arg   string
+	bound float64
+	re    *regexp.Regexp
+	oneOf []string
+}
+
+// newValidationRule creates a rule from name and arg to validate values of type t.
+func newValidationRule(name, arg string, t reflect.Type) (validationRule, error) {
+	r := validationRule{
+		name: name,
+		arg:  arg,
+	}
+
+	switch name {
+	case ruleMin, ruleMax:
+		b, err := parseBound(arg, t)
+		if err != nil {
+			return r, fmt.Errorf("invalid argument for %s: %q", name, arg)
+		}
+		r.bound = b
+	case ruleOneOf:
+		r.oneOf = strings.Fields(arg)
+	case ruleRegexp:
+		re, err := regexp.Compile(arg)
+		if err != nil {
+			return r, fmt.Errorf("invalid argument for %s: %s", name, err)
+		}
+		r.re = re
+	}
+
+	return r, nil
+}
+
+// parseBound parses the argument of a min or max rule. Bounds for durations are given as durations, bounds
+// for byte sizes as byte sizes and all other bounds as numbers.
+func parseBound(arg string, t reflect.Type) (float64, error) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch t {
+	case reflect.TypeOf(time.Duration(0)):
+		d, err := time.ParseDuration(arg)
+		return float64(d), err
+	case reflect.TypeOf(ByteSize(0)):
+		s, err := ParseByteSize(arg)
+		return float64(s), err
+	}
+	return strconv.ParseFloat(arg, 64)
+}
+
+func (r validationRule) String() string {
+	if r.arg == "" {
+		return r.name
+	}
+	return r.name + "=" + r.arg
+}
+
+// check checks v according to r. It returns a message describing the failure or the empty string if v is
+// valid.
+func (r validationRule) check(v reflect.Value) string {
+	switch r.name {
+	case ruleNonEmpty:
+		if isEmpty(v) {
+			return "must not be empty"
+		}
+
+	case ruleMin:
+		if m, ok := magnitude(v); ok && m < r.bound {
+			return fmt.Sprintf("%s is less than %s", describeMagnitude(v), r.arg)
+		}
+
+	case ruleMax:
+		if m, ok := magnitude(v); ok && m > r.bound {
+			return fmt.Sprintf("%s is greater than %s", describeMagnitude(v), r.arg)
+		}
+
+	case ruleOneOf:
+		s := validationString(v)
+		for _, o := range r.oneOf {
+			if s == o {
+				return ""
+			}
+		}
+		return fmt.Sprintf("%q is not one of %s", s, strings.Join(r.oneOf, ", "))
+
+	case ruleRegexp:
+		s := validationString(v)
+		if !r.re.MatchString(s) {
+			return fmt.Sprintf("%q does not match %s", s, r.arg)
+		}
+
+	case ruleURL:
+		s := validationString(v)
+		if u, err := url.Parse(s); err != nil || u.Scheme == "" || (u.Host == "" && u.Path == "") {
+			return fmt.Sprintf("%q is not a valid URL", s)
+		}
+	}
+
+	return ""
+}
+
+// validationString returns the string checked by oneof, regexp and url rules. For strings (including
+// named string types such as Secret, which format as ***) this is the underlying value.
+func validationString(v reflect.Value) string {
+	if v.Kind() == reflect.String {
+		return v.String()
+	}
+	return fmt.Sprint(v.Interface())
+}
+
+// isEmpty reports whether v is empty. Strings, slices, maps and arrays are empty when their length is 0; all
+// other values when they are the zero value.
+func isEmpty(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.String, reflect.Slice, reflect.Map, reflect.Array:
+		return v.Len() == 0
+	default:
+		return v.IsZero()
+	}
+}
+
+// magnitude returns the value compared by min and max rules: the value of a number or the length of a
+// string, slice, map or array.
+func magnitude(v reflect.Value) (float64, bool) {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	case reflect.String, reflect.Slice, reflect.Map, reflect.Array:
+		return float64(v.Len()), true
+	default:
+		return 0, false
+	}
+}
+
+func describeMagnitude(v reflect.Value) string {
+	switch v.Kind() {
+	case reflect.String, reflect.Slice, reflect.Map, reflect.Array:
+		return fmt.Sprintf("length %d", v.Len())
+	default:
+		return fmt.Sprintf("value %v", v.Interface())
+	}
+}
+
+// validate validates the value v bound to the key identified by path (and the Go field path field) using
+// rules. present signals whether a config value has been bound to v; src is the source of that value. All
+// rules but required are only checked for present values. Rules are checked against the value non-nil
+// pointers point to. Failures are recorded in b.
+func (b *binder) validate(path KeyPath, field string, v reflect.Value, present bool, src Source, rules []validationRule) {
+	t := v.Type()
+	for v.Kind() == reflect.Pointer && !v.IsNil() {
+		v = v.Elem()
+	}
+
+	for _, r := range rules {
+		var msg string
+		if r.name == ruleRequired {
+			if !present {
+				msg = "is required"
+			}
+		} else if present {
+			msg = r.check(v)
+		}
+
+		if msg != "" {
+			err := newBindError(t, path, field, &ValidationError{
+				Key:     path.Join(),
+				Rule:    r.String(),
+				Message: msg,
+			})
+			err.Source = src
+			b.record(err)
+		}
+	}
+}
@@ -0,0 +1,191 @@
+package appconf
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/halimath/assertthat-go/assert"
+	"github.com/halimath/assertthat-go/is"
+)
+
+func TestAppConfig_Bind_validation(t *testing.T) {
+	type (
+		DB struct {
+			Engine string `appconf:"type,required,oneof=mysql postgres"`
+			Host   string `appconf:",nonempty"`
+			Port   int    `appconf:",min=1,max=65535"`
+		}
+
+		Web struct {
+			Address string        `appconf:",regexp=^[a-z]+:[0-9]{2,5}$"`
+			Timeout time.Duration `appconf:",max=1s"`
+			URL     string        `appconf:"url,url"`
+		}
+
+		Auth struct {
+			Secret string `appconf:",required"`
+		}
+
+		Backend struct {
+			Host string
+			Tags []string `appconf:",max=1"`
+		}
+
+		Config struct {
+			DB       DB
+			Web      Web
+			Auth     Auth
+			Backends []Backend `appconf:",nonempty"`
+		}
+	)
+
+	c, err := New(
+		JSONFile("./testdata/config.json", true),
+		Static(map[string]interface{}{
+			"db.type": "sqlite",
+			"db.host": "",
+			"db.port": 0,
+			"web.url": "localhost:8080",
+		}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var config Config
+	err = c.Bind(&config)
+
+	assert.That(t, errors.Is(err, ErrValidation), is.Equal(true))
+	assert.That(t, validationErrors(t, err), is.DeepEqual([]*ValidationError{
+		{Key: "db.type", Rule: "oneof=mysql postgres", Message: `"sqlite" is not one of mysql, postgres`},
+		{Key: "db.host", Rule: "nonempty", Message: "must not be empty"},
+		{Key: "db.port", Rule: "min=1", Message: "value 0 is less than 1"},
+		{Key: "web.timeout", Rule: "max=1s", Message: "value 2s is greater than 1s"},
+		{Key: "web.url", Rule: "url", Message: `"localhost:8080" is not a valid URL`},
+		{Key: "auth.secret", Rule: "required", Message: "is required"},
+		{Key: "backends.0.tags", Rule: "max=1", Message: "length 2 is greater than 1"},
+		{Key: "backends.1.tags", Rule: "max=1", Message: "length 2 is greater than 1"},
+	}))
+
+	assert.That(t, config.Web.Address, is.Equal("localhost:8080"))
+}
+
+// validationErrors returns the ValidationError causes of err, which must be BindErrors.
+func validationErrors(t *testing.T, err error) []*ValidationError {
+	var bindErrs BindErrors
+	if !errors.As(err, &bindErrs) {
+		t.Fatalf("expected BindErrors but got %v", err)
+	}
+
+	valErrs := make([]*ValidationError, 0, len(bindErrs))
+	for _, e := range bindErrs {
+		var valErr *ValidationError
+		if errors.As(e, &valErr) {
+			valErrs = append(valErrs, valErr)
+		}
+	}
+	return valErrs
+}
+
+func TestAppConfig_Bind_validationSuccess(t *testing.T) {
+	type Config struct {
+		Name    string `appconf:",required,nonempty"`
+		Pattern string `appconf:",regexp=^a{1,3}$,required"`
+		Level   string `appconf:",oneof=debug info"`
+	}
+
+	c, err := New(Static(map[string]interface{}{
+		"name":    "test",
+		"pattern": "aa",
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var config Config
+	if err := c.Bind(&config); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.That(t, config, is.Equal(Config{Name: "test", Pattern: "aa"}))
+}
+
+func TestAppConfig_Bind_validationPointers(t *testing.T) {
+	type Config struct {
+		Name    *string        `appconf:",regexp=^a"`
+		Level   *string        `appconf:",oneof=debug info"`
+		URL     *string        `appconf:",url"`
+		Port    *int           `appconf:",max=65535"`
+		Timeout *time.Duration `appconf:",min=1s"`
+		Missing *int           `appconf:",min=1"`
+		Null    *string        `appconf:",nonempty"`
+	}
+
+	c, err := New(Static(map[string]interface{}{
+		"name":    "abc",
+		"level":   "trace",
+		"url":     "localhost",
+		"port":    70000,
+		"timeout": "10ms",
+		"null":    nil,
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var config Config
+	err = c.Bind(&config)
+
+	assert.That(t, validationErrors(t, err), is.DeepEqual([]*ValidationError{
+		{Key: "level", Rule: "oneof=debug info", Message: `"trace" is not one of debug, info`},
+		{Key: "url", Rule: "url", Message: `"localhost" is not a valid URL`},
+		{Key: "port", Rule: "max=65535", Message: "value 70000 is greater than 65535"},
+		{Key: "timeout", Rule: "min=1s", Message: "value 10ms is less than 1s"},
+	}))
+}
+
+func TestAppConfig_Bind_invalidValidationTag(t *testing.T) {
+	type Config struct {
+		Port int `appconf:",min=one"`
+	}
+
+	c, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var config Config
+	err = c.Bind(&config)
+
+	assert.That(t, errors.Is(err, ErrInvalidTag), is.Equal(true))
+}
+
+func TestAppConfig_Bind_unknownTagOption(t *testing.T) {
+	type Config struct {
+		Port  int    `appconf:"port,requird"`
+		Level int    `appconf:",default=1,requird"`
+		Name  string `appconf:",regexp=^a{1,3}$,nonempty"`
+	}
+
+	c, err := New(Static(map[string]interface{}{"name": "aa"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var config Config
+	err = c.Bind(&config)
+
+	assert.That(t, errors.Is(err, ErrInvalidTag), is.Equal(true))
+	assert.That(t, err.Error(), is.Equal(`port: invalid tag: unknown option "requird" (field Port); `+
+		`level: invalid tag: unknown option "requird" (field Level)`))
+}
+
+func TestSplitTagOptions(t *testing.T) {
+	assert.That(t, splitTagOptions("key,required,regexp=^a{1,3}$,min=1", false),
+		is.DeepEqual([]string{"key", "required", "regexp=^a{1,3}$", "min=1"}))
+	assert.That(t, splitTagOptions(",unknown", false), is.DeepEqual([]string{"", "unknown"}))
+	assert.That(t, splitTagOptions(",default=a,b,nonempty", true), is.DeepEqual([]string{"", "default=a,b", "nonempty"}))
+	assert.That(t, splitTagOptions(",default=1,requird", false), is.DeepEqual([]string{"", "default=1", "requird"}))
+	assert.That(t, splitTagOptions(",min=1,requird", true), is.DeepEqual([]string{"", "min=1", "requird"}))
+}
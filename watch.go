@@ -0,0 +1,88 @@
+package appconf
+
+import (
+	"context"
+	"os"
+	"time"
+)
+
+// Watch starts polling the files read by c's loaders (all loaders implementing FileLoader) every interval.
+// Whenever one of the files is modified, created or removed, c is reloaded (see Reload). Listeners registered
+// with OnChange are notified of successful reloads; errors are reported to listeners registered with OnError
+// and leave the current values in place.
+//
+// Watch returns immediately. Polling continues in a separate goroutine until ctx is done.
+func (c *AppConfig) Watch(ctx context.Context, interval time.Duration) {
+	var filenames []string
+	for _, l := range c.loaders {
+		if fl, ok := l.(FileLoader); ok {
+			filenames = append(filenames, fl.Filename())
+		}
+	}
+
+	stamps := statFiles(filenames)
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				current := statFiles(filenames)
+				if equalStamps(stamps, current) {
+					continue
+				}
+				stamps = current
+
+				if err := c.Reload(); err != nil {
+					c.notifyError(err)
+				}
+			}
+		}
+	}()
+}
+
+func (c *AppConfig) notifyError(err error) {
+	c.lock.RLock()
+	listeners := c.errorListeners
+	c.lock.RUnlock()
+
+	for _, l := range listeners {
+		l(err)
+	}
+}
+
+// fileStamp captures the attributes of a file used to detect modifications.
+type fileStamp struct {
+	exists  bool
+	modTime time.Time
+	size    int64
+}
+
+func statFiles(filenames []string) []fileStamp {
+	stamps := make([]fileStamp, len(filenames))
+	for i, f := range filenames {
+		info, err := os.Stat(f)
+		if err != nil {
+			continue
+		}
+		stamps[i] = fileStamp{
+			exists:  true,
+			modTime: info.ModTime(),
+			size:    info.Size(),
+		}
+	}
+	return stamps
+}
+
+func equalStamps(a, b []fileStamp) bool {
+	for i := range a {
+		if a[i].exists != b[i].exists || !a[i].modTime.Equal(b[i].modTime) || a[i].size != b[i].size {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,93 @@
+package appconf
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/halimath/assertthat-go/assert"
+	"github.com/halimath/assertthat-go/is"
+)
+
+func TestAppConfig_Watch(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "config.json")
+	writeFile(t, filename, `{"web": {"address": "localhost:8080"}}`)
+
+	c, err := New(JSONFile(filename, true))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	changes := make(chan [2]string, 1)
+	c.OnChange(func(old, new *AppConfig) {
+		changes <- [2]string{old.GetString("web.address"), new.GetString("web.address")}
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	c.Watch(ctx, 5*time.Millisecond)
+
+	writeFile(t, filename, `{"web": {"address": "localhost:9090"}}`)
+
+	select {
+	case got := <-changes:
+		assert.That(t, got, is.Equal([2]string{"localhost:8080", "localhost:9090"}))
+	case <-time.After(time.Second):
+		t.Fatal("no change notification received")
+	}
+
+	assert.That(t, c.GetString("web.address"), is.Equal("localhost:9090"))
+}
+
+func TestAppConfig_Watch_error(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "config.json")
+	writeFile(t, filename, `{"web": {"address": "localhost:8080"}}`)
+
+	c, err := New(JSONFile(filename, true))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	errs := make(chan error, 1)
+	c.OnError(func(err error) {
+		errs <- err
+	})
+	c.OnChange(func(old, new *AppConfig) {
+		t.Error("unexpected change notification")
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	c.Watch(ctx, 5*time.Millisecond)
+
+	writeFile(t, filename, `{"web": {"address": `)
+
+	select {
+	case <-errs:
+	case <-time.After(time.Second):
+		t.Fatal("no error notification received")
+	}
+
+	assert.That(t, c.GetString("web.address"), is.Equal("localhost:8080"))
+}
+
+// writeFile writes content to filename and moves the file's modification time forward to make sure the
+// change is detected even on file systems with a coarse timestamp resolution.
+func writeFile(t *testing.T, filename, content string) {
+	var modTime time.Time
+	if info, err := os.Stat(filename); err == nil {
+		modTime = info.ModTime().Add(time.Second)
+	} else {
+		modTime = time.Now()
+	}
+
+	if err := os.WriteFile(filename, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.Chtimes(filename, modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,159 @@
+package appconf
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// WriteJSON writes the configuration values of c to w as an indented JSON document. Map keys are written in
+// sorted order, so the output is stable. Secret values are written as *** (see WithSecretKeys). This applies
+// to all Write methods.
+func (c *AppConfig) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+	return enc.Encode(documentValue(c.root(), c.redactor(), false))
+}
+
+// WriteYAML writes the configuration values of c to w as a YAML document. Map keys are written in sorted
+// order, so the output is stable.
+func (c *AppConfig) WriteYAML(w io.Writer) error {
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(documentValue(c.root(), c.redactor(), false)); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+// WriteTOML writes the configuration values of c to w as a TOML document. Map keys are written in sorted
+// order, so the output is stable. TOML has no representation for null values, so keys with a null value are
+// omitted.
+func (c *AppConfig) WriteTOML(w io.Writer) error {
+	m, ok := documentValue(c.root(), c.redactor(), true).(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("%w: TOML requires a map of values", ErrUnsupportedValue)
+	}
+	enc := toml.NewEncoder(w)
+	enc.Indent = ""
+	return enc.Encode(m)
+}
+
+// WriteEnv writes the configuration values of c to w as lines of environment variable assignments in
+// dotenv format (NAME=value). The names are formed from prefix and the key paths the same way Env maps
+// variable names to keys, i.e. db.host becomes PREFIX_DB_HOST. Lists are written using the item's index
+// as key (SERVERS_0_HOST). Values containing whitespace or special characters are double quoted. The lines
+// are sorted by key.
+func (c *AppConfig) WriteEnv(w io.Writer, prefix string) error {
+	if len(prefix) > 0 && !strings.HasSuffix(prefix, "_") {
+		prefix += "_"
+	}
+
+	var b strings.Builder
+	writeEnvNode(&b, c.root(), nil, prefix, c.redactor())
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// writeEnvNode writes the assignments for n stored under path and all its descendants to b. Secret values
+// are redacted using r.
+func writeEnvNode(b *strings.Builder, n *Node, path KeyPath, prefix string, r redactor) {
+	switch n.Kind() {
+	case KindMap, KindList:
+		for _, k := range n.sortedKeys() {
+			writeEnvNode(b, n.Children[k], append(path[:len(path):len(path)], k), prefix, r)
+		}
+		return
+	}
+
+	value := n.Value
+	if r.redacts(path, n) {
+		value = redacted
+	}
+
+	name := strings.TrimSuffix(prefix, "_")
+	if len(path) > 0 {
+		name = prefix + strings.ToUpper(strings.ReplaceAll(path.Join(), KeySeparator, "_"))
+	}
+
+	b.WriteString(name)
+	b.WriteByte('=')
+	b.WriteString(quoteEnvValue(value))
+	b.WriteByte('\n')
+}
+
+// envPlainValuePattern matches values that can be written to a dotenv file without quoting.
+var envPlainValuePattern = regexp.MustCompile(`^[A-Za-z0-9_./:@%+,=*-]*$`)
+
+// envValueEscaper escapes the characters that need to be escaped in a double quoted dotenv value.
+var envValueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", `\r`, "\t", `\t`)
+
+// quoteEnvValue double quotes v if it contains characters that are not allowed in an unquoted dotenv value.
+func quoteEnvValue(v string) string {
+	if envPlainValuePattern.MatchString(v) {
+		return v
+	}
+	return `"` + envValueEscaper.Replace(v) + `"`
+}
+
+// documentValue converts the tree rooted at n to the generic value written by the serializers. An empty root
+// Node is converted to an empty map. See serializableValue for r and tomlDoc.
+func documentValue(n *Node, r redactor, tomlDoc bool) interface{} {
+	if n.Kind() == KindString && n.Value == "" && len(n.Children) == 0 {
+		return map[string]interface{}{}
+	}
+	return serializableValue(n, nil, r, tomlDoc)
+}
+
+// serializableValue converts n to a value that can be handled by the JSON, YAML and TOML encoders. Maps are
+// converted to map[string]interface{} and lists to []interface{}. Time values are converted to their
+// textual representation. If tomlDoc is set, time values are kept as time.Time to be written as TOML dates
+// and null values are omitted from maps, as TOML has no representation for them. path is the key path of n;
+// secret values are redacted using r.
+func serializableValue(n *Node, path KeyPath, r redactor, tomlDoc bool) interface{} {
+	if r.redacts(path, n) {
+		return redacted
+	}
+
+	switch n.Kind() {
+	case KindMap:
+		m := make(map[string]interface{}, len(n.Children))
+		for k, c := range n.Children {
+			if tomlDoc && c.Kind() == KindNull {
+				continue
+			}
+			m[string(k)] = serializableValue(c, append(path[:len(path):len(path)], k), r, tomlDoc)
+		}
+		return m
+
+	case KindList:
+		items := n.Items()
+		l := make([]interface{}, len(items))
+		for i, item := range items {
+			l[i] = serializableValue(item, append(path[:len(path):len(path)], indexKey(i)), r, tomlDoc)
+		}
+		return l
+
+	case KindTime:
+		if tomlDoc {
+			return n.raw
+		}
+		return n.Value
+
+	case KindNull:
+		return nil
+
+	case KindInt, KindFloat, KindBool:
+		return n.raw
+
+	default:
+		return n.Value
+	}
+}
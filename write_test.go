@@ -0,0 +1,178 @@
+package appconf
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/halimath/assertthat-go/assert"
+	"github.com/halimath/assertthat-go/is"
+)
+
+func TestAppConfig_Write_roundTrip(t *testing.T) {
+	c, err := New(JSONFile("./testdata/config.json", true))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		write func(*AppConfig, *bytes.Buffer) error
+		load  ReaderLoaderFunc
+	}{
+		"json": {func(c *AppConfig, b *bytes.Buffer) error { return c.WriteJSON(b) }, JSON},
+		"yaml": {func(c *AppConfig, b *bytes.Buffer) error { return c.WriteYAML(b) }, YAML},
+		"toml": {func(c *AppConfig, b *bytes.Buffer) error { return c.WriteTOML(b) }, TOML},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var b bytes.Buffer
+			if err := test.write(c, &b); err != nil {
+				t.Fatal(err)
+			}
+
+			assertLoader(t, LoaderFunc(func() (*Node, error) {
+				return test.load(&b)
+			}))
+		})
+	}
+}
+
+func TestAppConfig_WriteJSON(t *testing.T) {
+	c, err := New(Static(map[string]interface{}{
+		"web.address": "localhost:8080",
+		"web.timeout": "2s",
+		"db.port":     5432,
+		"db.ssl":      true,
+		"db.password": nil,
+		"tags":        []interface{}{"b", "a"},
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var b strings.Builder
+	if err := c.WriteJSON(&b); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.That(t, b.String(), is.Equal(`{
+  "db": {
+    "password": null,
+    "port": 5432,
+    "ssl": true
+  },
+  "tags": [
+    "b",
+    "a"
+  ],
+  "web": {
+    "address": "localhost:8080",
+    "timeout": "2s"
+  }
+}
+`))
+
+	b.Reset()
+	if err := c.Sub("web").WriteJSON(&b); err != nil {
+		t.Fatal(err)
+	}
+	assert.That(t, b.String(), is.Equal(`{
+  "address": "localhost:8080",
+  "timeout": "2s"
+}
+`))
+}
+
+func TestAppConfig_WriteYAML(t *testing.T) {
+	c, err := New(Static(map[string]interface{}{
+		"web.address": "localhost:8080",
+		"db.port":     5432,
+		"db.password": nil,
+		"day":         "2023-04-05",
+		"tags":        []interface{}{"b", "a"},
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var b strings.Builder
+	if err := c.WriteYAML(&b); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.That(t, b.String(), is.Equal(`day: "2023-04-05"
+db:
+  password: null
+  port: 5432
+tags:
+  - b
+  - a
+web:
+  address: localhost:8080
+`))
+}
+
+func TestAppConfig_WriteTOML(t *testing.T) {
+	c, err := TOML(strings.NewReader(`
+day = 2023-04-05
+title = "test"
+
+[db]
+port = 5432
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.Children["db"].Children["password"] = NewNullNode()
+
+	var b strings.Builder
+	if err := (&AppConfig{n: c}).WriteTOML(&b); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.That(t, b.String(), is.Equal(`day = 2023-04-05
+title = "test"
+
+[db]
+port = 5432
+`))
+
+	err = (&AppConfig{n: NewNode("scalar")}).WriteTOML(&b)
+	assert.That(t, err != nil, is.Equal(true))
+}
+
+func TestAppConfig_WriteEnv(t *testing.T) {
+	c, err := New(Static(map[string]interface{}{
+		"web.address": "localhost:8080",
+		"db.port":     5432,
+		"db.password": nil,
+		"motd":        "Hello \"world\"\nbye",
+		"servers": []interface{}{
+			map[string]interface{}{"host": "a"},
+			map[string]interface{}{"host": "b"},
+		},
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var b strings.Builder
+	if err := c.WriteEnv(&b, "APP"); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.That(t, b.String(), is.Equal(`APP_DB_PASSWORD=
+APP_DB_PORT=5432
+APP_MOTD="Hello \"world\"\nbye"
+APP_SERVERS_0_HOST=a
+APP_SERVERS_1_HOST=b
+APP_WEB_ADDRESS=localhost:8080
+`))
+
+	b.Reset()
+	if err := c.Sub("db").WriteEnv(&b, ""); err != nil {
+		t.Fatal(err)
+	}
+	assert.That(t, b.String(), is.Equal("PASSWORD=\nPORT=5432\n"))
+}